@@ -7,8 +7,10 @@ import (
 	"os/signal"
 	"pastebin/internal/config"
 	kafkaWriter "pastebin/internal/kafka"
+	"pastebin/internal/lib/tracing"
 	"pastebin/internal/worker"
 	"syscall"
+	"time"
 )
 
 const (
@@ -31,6 +33,19 @@ func main() {
 		slog.Int("batch", cfg.Kafka.BatchSize),
 	)
 
+	shutdownTracing, err := tracing.Setup(context.Background(),
+		cfg.Tracing.Enabled,
+		cfg.Tracing.Exporter,
+		cfg.Tracing.Endpoint,
+		cfg.Tracing.Insecure,
+		cfg.Tracing.SampleRatio,
+		cfg.Tracing.ServiceName,
+	)
+	if err != nil {
+		log.Error("failed to setup tracing", slog.String("err", err.Error()))
+		os.Exit(1)
+	}
+
 	p := kafkaWriter.New(
 		cfg.Kafka.Addr,
 		cfg.Kafka.Topic,
@@ -56,6 +71,14 @@ func main() {
 	}
 
 	<-ctx.Done()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Error("Tracing shutdown error", slog.String("err", err.Error()))
+	}
+
 	log.Info("Service gracefully stopped")
 }
 
@@ -8,9 +8,10 @@ import (
 )
 
 type Config struct {
-	Env   string `yaml:"env" env-default:"local"`
-	Kafka `yaml:"kafka"`
-	Hash  `yaml:"hash"`
+	Env     string `yaml:"env" env-default:"local"`
+	Kafka   `yaml:"kafka"`
+	Hash    `yaml:"hash"`
+	Tracing `yaml:"tracing"`
 }
 
 type Kafka struct {
@@ -26,6 +27,15 @@ type Hash struct {
 	Workers    int `yaml:"workers" env-default:"1"`
 }
 
+type Tracing struct {
+	Enabled     bool    `yaml:"enabled" env-default:"false"`
+	Exporter    string  `yaml:"exporter" env-default:"stdout"` // otlp | stdout
+	Endpoint    string  `yaml:"endpoint" env-default:"localhost:4318"`
+	Insecure    bool    `yaml:"insecure" env-default:"false"`
+	ServiceName string  `yaml:"service_name" env-default:"hash_generator"`
+	SampleRatio float64 `yaml:"sample_ratio" env-default:"1"`
+}
+
 func MustLoad() *Config {
 	configPath := "./config/config.yaml"
 
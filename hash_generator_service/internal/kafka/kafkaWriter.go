@@ -6,10 +6,17 @@ import (
 	"time"
 
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("hash_generator/internal/kafka")
+
 type KafkaWriter struct {
 	writer *kafka.Writer
+	topic  string
 }
 
 func New(addr, topic string, batchSize, maxAttemps int) *KafkaWriter {
@@ -24,25 +31,43 @@ func New(addr, topic string, batchSize, maxAttemps int) *KafkaWriter {
 				MaxAttempts:  maxAttemps,
 			},
 		),
+		topic: topic,
 	}
 }
 
-// * SendMessages отправляет []string messages от вокркера workerID
+// * SendMessages отправляет []string messages от вокркера workerID.
+// * Контекст трассировки записывается в заголовки каждого сообщения.
 func (p *KafkaWriter) SendMessages(ctx context.Context, workerID int, messages []string) error {
 	const op = "kafka.SendMessages"
 
+	ctx, span := tracer.Start(ctx, p.topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", p.topic),
+			attribute.Int("messaging.batch.message_count", len(messages)),
+			attribute.Int("worker.id", workerID),
+		),
+	)
+	defer span.End()
+
 	kafkaMsgs := make([]kafka.Message, 0, len(messages))
 
 	for _, msg := range messages {
-		kafkaMsgs = append(kafkaMsgs, kafka.Message{
+		km := kafka.Message{
 			Key:   fmt.Appendf(nil, "w%d-%d", workerID, time.Now().UnixNano()),
 			Value: []byte(msg),
 			Time:  time.Now(),
-		})
+		}
+		otel.GetTextMapPropagator().Inject(ctx, headerCarrier{headers: &km.Headers})
+
+		kafkaMsgs = append(kafkaMsgs, km)
 	}
 
 	err := p.writer.WriteMessages(ctx, kafkaMsgs...)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"main_service/internal/config"
+	"main_service/internal/lib/api/apikey"
+	"main_service/internal/storage"
+	"main_service/internal/storage/mysql"
+)
+
+// * apikey — административная команда для выдачи и отзыва API ключей.
+// *
+// *	apikey -user alice [-admin]   создаёт пользователя (если его нет) и выдаёт ему новый ключ
+// *	apikey -revoke pb_1a2b3c4d    отзывает ключ по префиксу
+func main() {
+	var (
+		configPath = flag.String("config", "./config/config.yaml", "path to config file")
+		userName   = flag.String("user", "", "user to issue a key for, created if missing")
+		isAdmin    = flag.Bool("admin", false, "create the user as admin")
+		revoke     = flag.String("revoke", "", "prefix of the key to revoke")
+	)
+	flag.Parse()
+
+	if (*userName == "") == (*revoke == "") {
+		fmt.Fprintln(os.Stderr, "exactly one of -user or -revoke is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg := config.MustLoad(*configPath)
+
+	db, err := mysql.New(cfg.MySQL.DSN)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect mysql: %s\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if *revoke != "" {
+		n, err := db.RevokeAPIKey(ctx, *revoke)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to revoke key: %s\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("revoked %d key(s) with prefix %s\n", n, *revoke)
+		return
+	}
+
+	if err := issue(ctx, db, *userName, *isAdmin); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to issue key: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func issue(ctx context.Context, db *mysql.Repository, name string, isAdmin bool) error {
+	var userID int64
+
+	user, err := db.UserByName(ctx, name)
+	switch {
+	case err == nil:
+		userID = user.ID
+	case errors.Is(err, storage.ErrUserNotFound):
+		userID, err = db.CreateUser(ctx, name, isAdmin)
+		if err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+
+	key, prefix, err := apikey.Generate()
+	if err != nil {
+		return err
+	}
+
+	if err := db.CreateAPIKey(ctx, userID, apikey.Hash(key), prefix); err != nil {
+		return err
+	}
+
+	// Ключ показывается один раз — в базе хранится только его хэш
+	fmt.Printf("user:   %s (id %d)\n", name, userID)
+	fmt.Printf("key:    %s\n", key)
+	fmt.Printf("prefix: %s\n", prefix)
+
+	return nil
+}
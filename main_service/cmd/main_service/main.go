@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,9 +12,46 @@ import (
 	"time"
 
 	"main_service/internal/config"
+	"main_service/internal/http-server/handlers/admin/dismiss"
+	"main_service/internal/http-server/handlers/admin/expire"
+	adminExtend "main_service/internal/http-server/handlers/admin/extend"
+	"main_service/internal/http-server/handlers/admin/jobs"
+	adminPastes "main_service/internal/http-server/handlers/admin/pastes"
+	"main_service/internal/http-server/handlers/admin/purge"
+	"main_service/internal/http-server/handlers/admin/reports"
+	"main_service/internal/http-server/handlers/admin/review"
+	"main_service/internal/http-server/handlers/admin/takedown"
+	"main_service/internal/http-server/handlers/admin/top"
+	"main_service/internal/http-server/handlers/diff"
+	"main_service/internal/http-server/handlers/health"
+	"main_service/internal/http-server/handlers/me/pastes"
+	"main_service/internal/http-server/handlers/search"
+	"main_service/internal/http-server/handlers/text/bundle"
+	"main_service/internal/http-server/handlers/text/extend"
+	"main_service/internal/http-server/handlers/text/file"
+	"main_service/internal/http-server/handlers/text/fork"
 	"main_service/internal/http-server/handlers/text/get"
+	"main_service/internal/http-server/handlers/text/meta"
+	"main_service/internal/http-server/handlers/text/raw"
+	"main_service/internal/http-server/handlers/text/remove"
+	"main_service/internal/http-server/handlers/text/report"
+	"main_service/internal/http-server/handlers/text/revisions"
 	"main_service/internal/http-server/handlers/text/save"
+	"main_service/internal/http-server/handlers/text/share"
+	"main_service/internal/http-server/handlers/text/update"
+	"main_service/internal/http-server/handlers/text/upload"
+	"main_service/internal/http-server/handlers/trending"
+	"main_service/internal/http-server/handlers/view"
 	kafkaReader "main_service/internal/kafka"
+	"main_service/internal/lib/api/notfound"
+	"main_service/internal/lib/secrets"
+	"main_service/internal/lib/signature"
+	"main_service/internal/lib/tracing"
+	"main_service/internal/middleware/audit"
+	"main_service/internal/middleware/auth"
+	enumGuard "main_service/internal/middleware/enum-guard"
+	rateLimit "main_service/internal/middleware/rate-limit"
+	requestTracing "main_service/internal/middleware/request-tracing"
 	swaggerAuth "main_service/internal/middleware/swagger-auth"
 	textService "main_service/internal/middleware/text"
 	cleanup "main_service/internal/scheduler"
@@ -33,6 +72,11 @@ import (
 // @host            localhost:8082
 // @BasePath        /
 
+// @securityDefinitions.apikey  ApiKeyAuth
+// @in                          header
+// @name                        X-API-Key
+// @description                 API ключ, выданный командой apikey. Также принимается как Authorization: Bearer <key>
+
 const (
 	envLocal = "local"
 	envDev   = "dev"
@@ -57,6 +101,19 @@ func main() {
 		cancel()
 	}()
 
+	shutdownTracing, err := tracing.Setup(ctx,
+		cfg.Tracing.Enabled,
+		cfg.Tracing.Exporter,
+		cfg.Tracing.Endpoint,
+		cfg.Tracing.Insecure,
+		cfg.Tracing.SampleRatio,
+		cfg.Tracing.ServiceName,
+	)
+	if err != nil {
+		log.Error("failed to setup tracing", slog.String("err", err.Error()))
+		os.Exit(1)
+	}
+
 	db, err := mysql.New(cfg.MySQL.DSN)
 	if err != nil {
 		log.Error("failed to connect mysql", slog.String("err", err.Error()))
@@ -85,13 +142,62 @@ func main() {
 
 	reader := kafkaReader.New(cfg.Kafka.Addr, cfg.Kafka.Topic)
 
-	textService := textService.New(db, reader, blobStorage, cache, cfg.Redis.PopularityThreshold)
+	textService := textService.New(db, reader, blobStorage, cache,
+		cfg.Redis.PopularityThreshold,
+		cfg.Enumeration.MissCacheTTL,
+	)
+
+	// Контекст обработки запросов: отменяется только после остановки сервера, чтобы дообслужить начатые запросы
+	serveCtx, stopServing := context.WithCancel(context.Background())
+	defer stopServing()
+
+	textService.WithBaseContext(serveCtx)
+	textService.WithAutoHide(cfg.Moderation.AutoHideReports)
+	textService.WithCacheTTL(cfg.Redis.CacheTTL)
+
+	if cfg.LocalCache.Enabled {
+		textService.WithLocalCache(cfg.LocalCache.MaxBytes, cfg.LocalCache.TTL, log)
+	}
+
+	if cfg.Secrets.Mode != secrets.ModeOff {
+		scanner, err := secretScanner(cfg.Secrets)
+		if err != nil {
+			log.Error("invalid secrets config", slog.String("err", err.Error()))
+			os.Exit(1)
+		}
+
+		textService.WithSecrets(scanner, cfg.Secrets.Mode, log)
+	}
+
+	checker := health.NewChecker(cfg.Health.Timeout,
+		health.Dependency{Name: "mysql", Pinger: db},
+		health.Dependency{Name: "minio", Pinger: blobStorage},
+		health.Dependency{Name: "redis", Pinger: cache},
+		health.Dependency{Name: "kafka", Pinger: reader},
+	)
+
+	signer, err := signature.New(cfg.Signing.ActiveKey, cfg.Signing.Keys)
+	if err != nil {
+		log.Error("failed to setup link signing", slog.String("err", err.Error()))
+		os.Exit(1)
+	}
 
-	router := setupRouter(ctx, log, textService, cfg)
+	cleaner := cleanup.New(db, textService, blobStorage, cache, log)
 
-	cleaner := cleanup.New(db, blobStorage, cache, log)
+	router := setupRouter(log, routerDeps{
+		texts:   textService,
+		limiter: cache,
+		tracker: cache,
+		users:   db,
+		signer:  signer,
+		checker: checker,
+		ranking: cache,
+		audits:  db,
+		jobs:    cleaner,
+	}, cfg)
 
 	go cleaner.Start(ctx, 3, 0)
+	textService.StartLocalCache(ctx, cfg.LocalCache.FlushEvery)
 
 	srv := &http.Server{
 		Addr:         cfg.Address,
@@ -99,6 +205,7 @@ func main() {
 		ReadTimeout:  cfg.HTTPServer.Timeout,
 		WriteTimeout: cfg.HTTPServer.Timeout,
 		IdleTimeout:  cfg.HTTPServer.IdleTimeout,
+		BaseContext:  func(net.Listener) context.Context { return serveCtx },
 	}
 
 	go func() {
@@ -111,6 +218,11 @@ func main() {
 
 	<-ctx.Done()
 
+	// Сначала выводим инстанс из ротации, затем останавливаем сервер
+	checker.Drain()
+	log.Info("Draining before shutdown", slog.Duration("delay", cfg.Health.DrainDelay))
+	time.Sleep(cfg.Health.DrainDelay)
+
 	log.Info("Shutting down HTTP server...")
 
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -121,21 +233,44 @@ func main() {
 	} else {
 		log.Info("Server stopped gracefully")
 	}
+	stopServing()
+
+	textService.FlushViews(shutdownCtx)
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Error("Tracing shutdown error", slog.String("err", err.Error()))
+	}
 
 	log.Info("Main service stopped")
 }
 
-func setupRouter(
-	ctx context.Context,
-	log *slog.Logger,
-	textService *textService.TextOperator,
-	cfg *config.Config,
-) *chi.Mux {
+// * routerDeps — зависимости обработчиков. Именованные поля вместо позиционных аргументов,
+// * потому что одно хранилище реализует несколько интерфейсов и аргументы легко перепутать.
+type routerDeps struct {
+	texts   *textService.TextOperator
+	limiter rateLimit.Limiter
+	tracker enumGuard.Tracker
+	users   auth.Users
+	signer  *signature.Signer
+	checker *health.Checker
+	ranking top.Ranking
+	audits  audit.Store
+	jobs    jobs.Jobs
+}
+
+func setupRouter(log *slog.Logger, d routerDeps, cfg *config.Config) *chi.Mux {
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
+	if cfg.RateLimit.TrustProxy {
+		r.Use(middleware.RealIP)
+	}
+	r.Use(requestTracing.New())
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 
+	r.Get("/healthz", health.Live())
+	r.Get("/readyz", health.Ready(log, d.checker))
+
 	if cfg.Swagger.Enabled {
 		r.Group(func(r chi.Router) {
 			r.Use(swaggerAuth.New(cfg.Swagger.Username, cfg.Swagger.Password))
@@ -143,12 +278,145 @@ func setupRouter(
 		})
 	}
 
-	r.Post("/text/save", save.New(ctx, log, textService, cfg.DefaultTTL))
-	r.Get("/text/{hash}", get.New(ctx, log, textService))
+	ipLimit, saveLimit, getLimit := passThrough, passThrough, passThrough
+	if cfg.RateLimit.Enabled {
+		ipLimit = rateLimit.New(log, d.limiter, "all", cfg.RateLimit.IP.Rate, cfg.RateLimit.IP.Burst)
+		saveLimit = rateLimit.New(log, d.limiter, "save", cfg.RateLimit.Save.Rate, cfg.RateLimit.Save.Burst)
+		getLimit = rateLimit.New(log, d.limiter, "get", cfg.RateLimit.Get.Rate, cfg.RateLimit.Get.Burst)
+	}
+
+	notFound := notfound.Policy{
+		Uniform:    cfg.Enumeration.UniformNotFound,
+		MinLatency: cfg.Enumeration.NotFoundMinLatency,
+	}
+
+	guard := passThrough
+	if cfg.Enumeration.Enabled {
+		guard = enumGuard.New(log, d.tracker,
+			cfg.Enumeration.Window,
+			cfg.Enumeration.MaxMisses,
+			cfg.Enumeration.BaseBan,
+			cfg.Enumeration.MaxBan,
+		)
+	}
+
+	r.Group(func(r chi.Router) {
+		// Каждый API ключ проверяется запросом в MySQL, поэтому перебор ключей ограничивается по IP до проверки
+		r.Use(ipLimit)
+		r.Use(auth.New(log, d.users, !cfg.Auth.RequireAPIKey))
+
+		r.With(saveLimit).Post("/text/save", save.New(log, d.texts, cfg.DefaultTTL, cfg.Timeouts.Save))
+		r.With(getLimit, guard).Get("/text/{hash}", get.New(log, d.texts, d.signer,
+			cfg.Timeouts.Get,
+			notFound,
+			cfg.HTTPCache.MaxAge,
+			cfg.View.DefaultTheme,
+			cfg.View.MaxSize,
+		))
+		r.With(getLimit).Get("/text/{hash}/revisions", revisions.New(log, d.texts, cfg.Timeouts.Get, notFound))
+		r.With(saveLimit).Post("/upload", upload.New(
+			log,
+			d.texts,
+			cfg.Uploads.Types,
+			cfg.Uploads.MaxSize,
+			cfg.DefaultTTL,
+			cfg.Timeouts.Save,
+		))
+		r.With(getLimit).Get("/search", search.New(log, d.texts, cfg.Timeouts.Get))
+		r.With(getLimit).Get("/trending", trending.New(log, d.texts, cfg.Timeouts.Get))
+		r.With(getLimit, guard).Get("/view/{hash}", view.New(
+			log,
+			d.texts,
+			d.signer,
+			cfg.View.DefaultTheme,
+			cfg.View.MaxSize,
+			cfg.Timeouts.Get,
+			notFound,
+		))
+		r.With(getLimit, guard).Get("/text/{hash}/raw", raw.New(log, d.texts, d.signer,
+			cfg.Timeouts.Get,
+			notFound,
+		))
+		r.With(getLimit, guard).Get("/text/{hash}/meta", meta.New(log, d.texts, cfg.Timeouts.Get, notFound))
+		r.With(getLimit, guard).Get("/text/{hash}/files/{filename}", file.New(log, d.texts, cfg.Timeouts.Get, notFound))
+		r.With(getLimit, guard).Get("/text/{hash}/bundle", bundle.New(log, d.texts, cfg.Timeouts.Get, notFound))
+		r.With(saveLimit).Post("/text/{hash}/fork", fork.New(log, d.texts, cfg.DefaultTTL, cfg.Timeouts.Save, notFound))
+		r.With(saveLimit).Post("/text/{hash}/report", report.New(log, d.texts, cfg.Timeouts.Save, notFound))
+		r.With(getLimit, guard).Get("/diff/{hashA}/{hashB}", diff.New(
+			log,
+			d.texts,
+			cfg.Timeouts.Get,
+			cfg.Diff.MaxSize,
+			cfg.Diff.DefaultContext,
+			cfg.Diff.MaxContext,
+			notFound,
+		))
+
+		r.Group(func(r chi.Router) {
+			r.Use(auth.Required)
+
+			r.With(getLimit).Get("/me/pastes", pastes.New(log, d.texts, cfg.Timeouts.Get))
+			r.With(saveLimit).Put("/text/{hash}", update.New(log, d.texts, cfg.Timeouts.Save, notFound))
+			r.With(saveLimit).Delete("/text/{hash}", remove.New(log, d.texts, cfg.Timeouts.Save, notFound))
+			r.With(saveLimit).Post("/text/{hash}/extend", extend.New(log, d.texts, cfg.Timeouts.Save, notFound))
+			r.With(saveLimit).Post("/text/{hash}/share", share.New(log, d.texts, d.signer,
+				cfg.Signing.BaseURL, cfg.Signing.DefaultTTL, cfg.Signing.MaxTTL, cfg.Timeouts.Save, notFound))
+		})
+
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(auth.Admin)
+			r.Use(audit.New(log, d.audits))
+
+			r.Get("/pastes", adminPastes.New(log, d.texts, cfg.Timeouts.Get))
+			r.Post("/pastes/{hash}/expire", expire.New(log, d.texts, cfg.Timeouts.Save))
+			r.Post("/pastes/{hash}/extend", adminExtend.New(log, d.texts, cfg.Timeouts.Save))
+			r.Delete("/pastes/{hash}", purge.New(log, d.texts, cfg.Timeouts.Save))
+			r.Get("/top", top.New(log, d.ranking, cfg.Timeouts.Get))
+			r.Post("/jobs/{job}", jobs.New(log, d.jobs, cfg.Admin.JobTimeout))
+
+			r.Get("/reports", reports.New(log, d.texts, cfg.Timeouts.Get))
+			r.Get("/reports/{hash}", review.New(log, d.texts, cfg.Timeouts.Get, notFound))
+			r.Post("/reports/{hash}/takedown", takedown.New(log, d.texts, cfg.Timeouts.Save, notFound))
+			r.Post("/reports/{hash}/dismiss", dismiss.New(log, d.texts, cfg.Timeouts.Save, notFound))
+		})
+	})
 
 	return r
 }
 
+// * secretScanner собирает правила поиска секретов: встроенные без disabled_rules и дополнительные из конфига
+func secretScanner(cfg config.Secrets) (*secrets.Scanner, error) {
+	if cfg.Mode != secrets.ModeReject && cfg.Mode != secrets.ModeRedact {
+		return nil, fmt.Errorf("unknown mode %q", cfg.Mode)
+	}
+
+	disabled := make(map[string]bool, len(cfg.DisabledRules))
+	for _, name := range cfg.DisabledRules {
+		disabled[name] = true
+	}
+
+	var rules []secrets.Rule
+	for _, rule := range secrets.DefaultRules() {
+		if !disabled[rule.Name] {
+			rules = append(rules, rule)
+		}
+	}
+
+	for _, r := range cfg.Rules {
+		rule, err := secrets.NewRule(r.Name, r.Pattern, r.Group, r.MinEntropy)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return secrets.New(rules), nil
+}
+
+func passThrough(next http.Handler) http.Handler {
+	return next
+}
+
 func setupLogger(env string) *slog.Logger {
 	var log *slog.Logger
 
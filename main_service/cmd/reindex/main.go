@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"main_service/internal/config"
+	textService "main_service/internal/middleware/text"
+	"main_service/internal/storage"
+	minioStorage "main_service/internal/storage/minio"
+	"main_service/internal/storage/mysql"
+)
+
+// * сколько хэшей читаем из MySQL за раз
+const batchSize = 500
+
+// * reindex — административная команда, которая заново строит полнотекстовый индекс
+// * по содержимому всех неистёкших текстов из MinIO.
+// *
+// *	reindex [-config ./config/config.yaml]
+func main() {
+	configPath := flag.String("config", "./config/config.yaml", "path to config file")
+	flag.Parse()
+
+	cfg := config.MustLoad(*configPath)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := mysql.New(cfg.MySQL.DSN)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect mysql: %s\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	blobStorage, err := minioStorage.New(ctx, cfg.MinIO.Endpoint, cfg.MinIO.User, cfg.MinIO.Password, cfg.MinIO.Bucket, cfg.MinIO.UseSSL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect minio: %s\n", err)
+		os.Exit(1)
+	}
+
+	// Reindex обращается только к MySQL и MinIO, Kafka и redis не нужны
+	texts := textService.New(db, nil, blobStorage, nil, cfg.Redis.PopularityThreshold, 0)
+
+	var indexed, failed int
+	after := ""
+	for {
+		hashes, err := db.ActiveHashes(ctx, after, batchSize)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to list pastes: %s\n", err)
+			os.Exit(1)
+		}
+		if len(hashes) == 0 {
+			break
+		}
+
+		for _, hash := range hashes {
+			err := texts.Reindex(ctx, hash)
+			switch {
+			case err == nil:
+				indexed++
+			case errors.Is(err, storage.ErrTextNotFound), errors.Is(err, storage.ErrTTLIsExpired):
+				// Текст удалили или он истёк, пока шла переиндексация
+			case ctx.Err() != nil:
+				fmt.Fprintln(os.Stderr, "interrupted")
+				os.Exit(1)
+			default:
+				failed++
+				fmt.Fprintf(os.Stderr, "failed to reindex %s: %s\n", hash, err)
+			}
+		}
+
+		after = hashes[len(hashes)-1]
+	}
+
+	fmt.Printf("indexed %d paste(s), %d failed\n", indexed, failed)
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
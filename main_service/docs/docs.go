@@ -15,16 +15,4182 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/jobs/{job}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Запускает в фоне cleanup (удаление истёкших текстов, обычно раз в сутки) или reconcile (удаление из MinIO объектов, на которые не ссылается ни один текст). Результат пишется в лог. Только для администраторов.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Запустить задачу обслуживания",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "cleanup или reconcile",
+                        "name": "job",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Задача запущена\"  example({\"status\": \"OK\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API ключ не передан или неверен\"  example({\"status\": \"error\", \"error\": \"API key is required\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь не администратор\"  example({\"status\": \"error\", \"error\": \"Forbidden\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Неизвестная задача\"  example({\"status\": \"error\", \"error\": \"Unknown job\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Задача уже выполняется\"  example({\"status\": \"error\", \"error\": \"Job is already running\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/pastes": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает тексты всех пользователей по фильтрам, новые первыми. Время — в RFC 3339, размер — размер текущей ревизии в байтах. Истёкшие, но ещё не удалённые тексты показываются только с include_expired. Только для администраторов.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Список текстов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2026-10-01T00:00:00Z",
+                        "description": "Созданы не раньше",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Созданы раньше",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Истекают не раньше",
+                        "name": "expires_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Истекают раньше",
+                        "name": "expires_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Минимальный размер",
+                        "name": "min_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальный размер",
+                        "name": "max_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Владелец",
+                        "name": "owner_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Показывать истёкшие",
+                        "name": "include_expired",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Размер страницы (1-500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница текстов",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "limit": {
+                                    "type": "integer"
+                                },
+                                "offset": {
+                                    "type": "integer"
+                                },
+                                "pastes": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "content_type": {
+                                                "type": "string"
+                                            },
+                                            "created_at": {
+                                                "type": "string"
+                                            },
+                                            "expires_at": {
+                                                "type": "string"
+                                            },
+                                            "hash": {
+                                                "type": "string"
+                                            },
+                                            "language": {
+                                                "type": "string"
+                                            },
+                                            "moderation": {
+                                                "type": "string"
+                                            },
+                                            "owner_id": {
+                                                "type": "integer"
+                                            },
+                                            "size": {
+                                                "type": "integer"
+                                            },
+                                            "visibility": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                },
+                                "status": {
+                                    "type": "string"
+                                },
+                                "total": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный фильтр\"  example({\"status\": \"error\", \"error\": \"Invalid created_from\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API ключ не передан или неверен\"  example({\"status\": \"error\", \"error\": \"API key is required\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь не администратор\"  example({\"status\": \"error\", \"error\": \"Forbidden\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/pastes/{hash}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет любой текст, в том числе истёкший, из MySQL, MinIO, кэша redis, рейтинга популярности и negative cache. Только для администраторов.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Удалить текст отовсюду",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальный хеш текста",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Текст удалён\"  example({\"status\": \"OK\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API ключ не передан или неверен\"  example({\"status\": \"error\", \"error\": \"API key is required\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь не администратор\"  example({\"status\": \"error\", \"error\": \"Forbidden\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Текст не найден\"  example({\"status\": \"error\", \"error\": \"Text not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/pastes/{hash}/expire": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Сразу делает текст истёкшим и убирает его из кэша; данные удалит ближайшая очистка. Только для администраторов.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Истечь текст",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальный хеш текста",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Текст истёк\"  example({\"status\": \"OK\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API ключ не передан или неверен\"  example({\"status\": \"error\", \"error\": \"API key is required\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь не администратор\"  example({\"status\": \"error\", \"error\": \"Forbidden\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Текст не найден\"  example({\"status\": \"error\", \"error\": \"Text not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/pastes/{hash}/extend": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Сдвигает время истечения любого текста на ttl дней. Истёкший, но ещё не удалённый текст продлевается от текущего момента. Только для администраторов.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Продлить текст",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальный хеш текста",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "На сколько дней продлить",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "ttl": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Новое время истечения\"  example({\"status\": \"OK\", \"expires_at\": \"2026-10-26T12:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос\"  example({\"status\": \"error\", \"error\": \"Field TTL is a required field\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API ключ не передан или неверен\"  example({\"status\": \"error\", \"error\": \"API key is required\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь не администратор\"  example({\"status\": \"error\", \"error\": \"Forbidden\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Текст не найден\"  example({\"status\": \"error\", \"error\": \"Text not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reports": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает тексты с жалобами, кроме уже удалённых: сначала автоматически скрытые, затем по числу жалоб. Только для администраторов.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Очередь модерации",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница очереди",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "limit": {
+                                    "type": "integer"
+                                },
+                                "offset": {
+                                    "type": "integer"
+                                },
+                                "pastes": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "created_at": {
+                                                "type": "string"
+                                            },
+                                            "expires_at": {
+                                                "type": "string"
+                                            },
+                                            "hash": {
+                                                "type": "string"
+                                            },
+                                            "last_reported_at": {
+                                                "type": "string"
+                                            },
+                                            "moderation": {
+                                                "type": "string"
+                                            },
+                                            "owner_id": {
+                                                "type": "integer"
+                                            },
+                                            "reports": {
+                                                "type": "integer"
+                                            },
+                                            "visibility": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                },
+                                "status": {
+                                    "type": "string"
+                                },
+                                "total": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры пагинации\"  example({\"status\": \"error\", \"error\": \"Invalid limit\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API ключ не передан или неверен\"  example({\"status\": \"error\", \"error\": \"API key is required\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь не администратор\"  example({\"status\": \"error\", \"error\": \"Forbidden\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reports/{hash}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает текст вместе с жалобами на него, в том числе скрытый. Бинарное содержимое не включается в ответ; у удалённого текста содержимого нет. Только для администраторов.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Текст на модерации",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальный хеш текста",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Текст и жалобы",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "content_type": {
+                                    "type": "string"
+                                },
+                                "created_at": {
+                                    "type": "string"
+                                },
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "hash": {
+                                    "type": "string"
+                                },
+                                "language": {
+                                    "type": "string"
+                                },
+                                "moderation": {
+                                    "type": "string"
+                                },
+                                "moderation_reason": {
+                                    "type": "string"
+                                },
+                                "owner_id": {
+                                    "type": "integer"
+                                },
+                                "reports": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "comment": {
+                                                "type": "string"
+                                            },
+                                            "created_at": {
+                                                "type": "string"
+                                            },
+                                            "reason": {
+                                                "type": "string"
+                                            },
+                                            "reporter_id": {
+                                                "type": "integer"
+                                            }
+                                        }
+                                    }
+                                },
+                                "status": {
+                                    "type": "string"
+                                },
+                                "text": {
+                                    "type": "string"
+                                },
+                                "visibility": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API ключ не передан или неверен\"  example({\"status\": \"error\", \"error\": \"API key is required\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь не администратор\"  example({\"status\": \"error\", \"error\": \"Forbidden\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Текст не найден или истёк\"  example({\"status\": \"error\", \"error\": \"Text not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)\"  example({\"status\": \"error\", \"error\": \"Text has expired\", \"expires_at\": \"2026-10-19T12:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reports/{hash}/dismiss": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет жалобы на текст и возвращает автоматически скрытый текст читателям. Удалённый текст восстановить нельзя. Только для администраторов.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Отклонить жалобы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальный хеш текста",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Жалобы отклонены\"  example({\"status\": \"OK\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API ключ не передан или неверен\"  example({\"status\": \"error\", \"error\": \"API key is required\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь не администратор\"  example({\"status\": \"error\", \"error\": \"Forbidden\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Текст не найден или истёк\"  example({\"status\": \"error\", \"error\": \"Text not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Текст уже удалён модератором\"  example({\"status\": \"error\", \"error\": \"Text is already taken down\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)\"  example({\"status\": \"error\", \"error\": \"Text has expired\", \"expires_at\": \"2026-10-19T12:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reports/{hash}/takedown": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет содержимое текста из MySQL, MinIO и кэша, оставляя метаданные: дальше запросы к тексту получают 451 с указанной причиной. Только для администраторов.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Удалить текст по жалобе",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальный хеш текста",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина, которую увидят читатели",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "reason": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Текст удалён\"  example({\"status\": \"OK\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос\"  example({\"status\": \"error\", \"error\": \"Field Reason is a required field\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API ключ не передан или неверен\"  example({\"status\": \"error\", \"error\": \"API key is required\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь не администратор\"  example({\"status\": \"error\", \"error\": \"Forbidden\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Текст не найден или истёк\"  example({\"status\": \"error\", \"error\": \"Text not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)\"  example({\"status\": \"error\", \"error\": \"Text has expired\", \"expires_at\": \"2026-10-19T12:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/top": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает тексты с наибольшим числом просмотров из рейтинга популярности в redis. В рейтинге могут быть истёкшие и приватные тексты. Только для администраторов.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Самые популярные тексты",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Сколько текстов вернуть (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Рейтинг",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "pastes": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "hash": {
+                                                "type": "string"
+                                            },
+                                            "views": {
+                                                "type": "integer"
+                                            }
+                                        }
+                                    }
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный limit\"  example({\"status\": \"error\", \"error\": \"Invalid limit\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API ключ не передан или неверен\"  example({\"status\": \"error\", \"error\": \"API key is required\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь не администратор\"  example({\"status\": \"error\", \"error\": \"Forbidden\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/diff/{hashA}/{hashB}": {
+            "get": {
+                "security": [
+                    {
+                        "none": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Построчно сравнивает два текста (или две ревизии одного текста) и возвращает unified diff и список hunk'ов. Тексты загружаются так же, как в GET /text/{hash}, поэтому популярные тексты берутся из Redis. Размер каждого текста ограничен.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "texts"
+                ],
+                "summary": "Сравнить два текста",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Хеш первого (исходного) текста",
+                        "name": "hashA",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Хеш второго текста",
+                        "name": "hashB",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ревизия первого текста, по умолчанию текущая",
+                        "name": "rev_a",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ревизия второго текста, по умолчанию текущая",
+                        "name": "rev_b",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 3,
+                        "description": "Количество строк контекста вокруг изменений",
+                        "name": "context",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результат сравнения",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "context": {
+                                    "type": "integer"
+                                },
+                                "diff": {
+                                    "type": "string"
+                                },
+                                "hash_a": {
+                                    "type": "string"
+                                },
+                                "hash_b": {
+                                    "type": "string"
+                                },
+                                "hunks": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "lines": {
+                                                "type": "array",
+                                                "items": {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "op": {
+                                                            "type": "string"
+                                                        },
+                                                        "text": {
+                                                            "type": "string"
+                                                        }
+                                                    }
+                                                }
+                                            },
+                                            "new_lines": {
+                                                "type": "integer"
+                                            },
+                                            "new_start": {
+                                                "type": "integer"
+                                            },
+                                            "old_lines": {
+                                                "type": "integer"
+                                            },
+                                            "old_start": {
+                                                "type": "integer"
+                                            }
+                                        }
+                                    }
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры\"  example({\"status\": \"error\", \"error\": \"Invalid context\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Текст или ревизия не найдены\"  example({\"status\": \"error\", \"error\": \"Text not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)\"  example({\"status\": \"error\", \"error\": \"Text has expired\", \"expires_at\": \"2026-10-19T12:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Текст слишком большой для сравнения\"  example({\"status\": \"error\", \"error\": \"Text is too large to diff\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Один из текстов бинарный\"  example({\"status\": \"error\", \"error\": \"Binary content can't be diffed\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов, см. Retry-After\"  example({\"status\": \"error\", \"error\": \"Too many requests\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "451": {
+                        "description": "Текст скрыт или удалён модератором\"  example({\"status\": \"error\", \"error\": \"Text is unavailable\", \"reason\": \"Phishing\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "reason": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Сравнение не уложилось в таймаут\"  example({\"status\": \"error\", \"error\": \"Request timed out\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "security": [
+                    {
+                        "none": []
+                    }
+                ],
+                "description": "Сообщает, что процесс запущен. Зависимости не проверяются.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "Процесс жив\"  example({\"status\": \"OK\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/me/pastes": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает тексты владельца API ключа, новые первыми. Истёкшие тексты не попадают в список.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Мои тексты",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница текстов",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "limit": {
+                                    "type": "integer"
+                                },
+                                "offset": {
+                                    "type": "integer"
+                                },
+                                "pastes": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "created_at": {
+                                                "type": "string"
+                                            },
+                                            "expires_at": {
+                                                "type": "string"
+                                            },
+                                            "hash": {
+                                                "type": "string"
+                                            },
+                                            "visibility": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                },
+                                "status": {
+                                    "type": "string"
+                                },
+                                "total": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры пагинации\"  example({\"status\": \"error\", \"error\": \"Invalid limit\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API ключ не передан или неверен\"  example({\"status\": \"error\", \"error\": \"API key is required\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "security": [
+                    {
+                        "none": []
+                    }
+                ],
+                "description": "Пингует MySQL, MinIO, Redis и Kafka и возвращает статус каждой зависимости. Во время graceful shutdown всегда отвечает 503.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "Все зависимости доступны\"  example({\"status\": \"OK\", \"checks\": {\"mysql\": {\"status\": \"up\", \"latency_ms\": 2}}})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "checks": {
+                                    "type": "object"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Сервис не готов принимать трафик\"  example({\"status\": \"Error\", \"error\": \"Dependency is unavailable\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "checks": {
+                                    "type": "object"
+                                },
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "security": [
+                    {
+                        "none": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Полнотекстовый поиск по неистёкшим текстам. Ищет среди публичных текстов и собственных текстов владельца API ключа. Для каждого результата возвращается фрагмент вокруг найденного слова.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "texts"
+                ],
+                "summary": "Поиск по содержимому",
+                "parameters": [
+                    {
+                        "maxLength": 256,
+                        "type": "string",
+                        "description": "Поисковый запрос",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (1-50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Найденные тексты",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "limit": {
+                                    "type": "integer"
+                                },
+                                "offset": {
+                                    "type": "integer"
+                                },
+                                "results": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "created_at": {
+                                                "type": "string"
+                                            },
+                                            "hash": {
+                                                "type": "string"
+                                            },
+                                            "language": {
+                                                "type": "string"
+                                            },
+                                            "score": {
+                                                "type": "number"
+                                            },
+                                            "snippet": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры\"  example({\"status\": \"error\", \"error\": \"Query is required\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов, см. Retry-After\"  example({\"status\": \"error\", \"error\": \"Too many requests\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Поиск не уложился в таймаут\"  example({\"status\": \"error\", \"error\": \"Request timed out\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/text/save": {
             "post": {
                 "security": [
                     {
                         "none": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Сохраняет текст в хранилище и возвращает уникальный хеш для последующего доступа. Текст хранится с указанным TTL (время жизни). Если передан API ключ, текст привязывается к его владельцу. Вместо text можно передать files — тогда сохраняется многофайловый текст (до 50 файлов с уникальными именами). Если язык не указан, он определяется по shebang, modeline, расширению filename и содержимому. Текст проверяется на ключи, токены и приватные ключи: такой текст отклоняется (422 с номерами строк) или сохраняется с заменой секретов на [REDACTED:\u003cправило\u003e].",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "texts"
+                ],
+                "summary": "Сохранить текст",
+                "parameters": [
+                    {
+                        "description": "Данные для сохранения. visibility: public (по умолчанию), unlisted или private (только с API ключом). on_secret: reject или redact — что делать с найденными ключами и токенами (по умолчанию как в конфиге)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "filename": {
+                                    "type": "string"
+                                },
+                                "files": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "content": {
+                                                "type": "string"
+                                            },
+                                            "filename": {
+                                                "type": "string"
+                                            },
+                                            "language": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                },
+                                "language": {
+                                    "type": "string"
+                                },
+                                "on_secret": {
+                                    "type": "string"
+                                },
+                                "text": {
+                                    "type": "string"
+                                },
+                                "ttl": {
+                                    "type": "integer"
+                                },
+                                "visibility": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Текст успешно сохранен\"  example({\"status\": \"ok\", \"hash\": \"a1b2c3d4e5f6\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "hash": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос\"  example({\"status\": \"error\", \"error\": \"Text is required\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный API ключ или анонимный доступ выключен\"  example({\"status\": \"error\", \"error\": \"Invalid API key\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "В тексте найдены секреты\"  example({\"status\": \"error\", \"error\": \"Text contains secrets\", \"secrets\": [{\"line\": 3, \"rule\": \"aws_access_key_id\"}]})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "secrets": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "file": {
+                                                "type": "string"
+                                            },
+                                            "line": {
+                                                "type": "integer"
+                                            },
+                                            "rule": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов, см. Retry-After\"  example({\"status\": \"error\", \"error\": \"Too many requests\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Failed to save text\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Сохранение не уложилось в таймаут\"  example({\"status\": \"error\", \"error\": \"Request timed out\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                },
+                "x-order": 1
+            }
+        },
+        "/text/{hash}": {
+            "get": {
+                "security": [
+                    {
+                        "none": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получает сохраненный текст по его уникальному хешу. Популярные тексты кэшируются в Redis для быстрого доступа. Приватный текст доступен владельцу или по подписанной ссылке (параметры expires, kid, sig).\nФормат ответа выбирается по Accept: application/json (по умолчанию) — JSON с текстом, text/plain — сам текст, text/html — страница с подсветкой, application/octet-stream — скачивание файлом. Ответ сжимается br или gzip по Accept-Encoding.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/plain",
+                    "text/html",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "texts"
+                ],
+                "summary": "Получить текст",
+                "parameters": [
+                    {
+                        "maxLength": 64,
+                        "minLength": 6,
+                        "type": "string",
+                        "example": "a1b2c3d4e5f6",
+                        "description": "Уникальный хеш текста (буквенно-цифровая строка)",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер ревизии, по умолчанию текущая",
+                        "name": "rev",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Время истечения подписанной ссылки (unix)",
+                        "name": "expires",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор ключа подписи",
+                        "name": "kid",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "HMAC подпись ссылки",
+                        "name": "sig",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "application/json, text/plain, text/html или application/octet-stream",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "br, gzip",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из предыдущего ответа",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified из предыдущего ответа",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Текст успешно получен\"  example({\"status\": \"ok\", \"text\": \"Hello, World!\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "status": {
+                                    "type": "string"
+                                },
+                                "text": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "public для неизменяемых публичных текстов, max-age не больше оставшегося срока жизни"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "sha256 содержимого с суффиксом представления и сжатия"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Время создания ревизии"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Содержимое не изменилось"
+                    },
+                    "400": {
+                        "description": "Хеш не указан или некорректная ревизия\"  example({\"status\": \"error\", \"error\": \"Hash is empty\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Подпись ссылки неверна или истекла\"  example({\"status\": \"error\", \"error\": \"Invalid or expired signature\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Текст не найден\"  example({\"status\": \"error\", \"error\": \"Text not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "Ни один из форматов Accept не поддерживается\"  example({\"status\": \"error\", \"error\": \"Not acceptable\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)\"  example({\"status\": \"error\", \"error\": \"Text has expired\", \"expires_at\": \"2026-10-19T12:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Текст слишком большой для подсветки (text/html)\"  example({\"status\": \"error\", \"error\": \"Text is too large to highlight\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Содержимое бинарное, его нужно скачивать через /text/{hash}/raw\"  example({\"status\": \"error\", \"error\": \"Content is binary, download it from /text/a1b2c3/raw\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов или клиент забанен за перебор хэшей, см. Retry-After\"  example({\"status\": \"error\", \"error\": \"Too many requests\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "451": {
+                        "description": "Текст скрыт или удалён модератором\"  example({\"status\": \"error\", \"error\": \"Text is unavailable\", \"reason\": \"Phishing\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "reason": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении текста\"  example({\"status\": \"error\", \"error\": \"Failed to get text\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Получение не уложилось в таймаут\"  example({\"status\": \"error\", \"error\": \"Request timed out\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                },
+                "x-order": 2
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Сохраняет новую ревизию текста. Предыдущие ревизии остаются доступны через параметр rev. Доступно только владельцу текста. Найденные в тексте секреты обрабатываются так, как задано в конфиге (secrets.mode).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "texts"
+                ],
+                "summary": "Изменить свой текст",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальный хеш текста",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новое содержимое",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "text": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ревизия создана\"  example({\"status\": \"OK\", \"hash\": \"a1b2c3\", \"rev\": 2})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "hash": {
+                                    "type": "string"
+                                },
+                                "rev": {
+                                    "type": "integer"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос\"  example({\"status\": \"error\", \"error\": \"Field Text is a required field\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API ключ не передан или неверен\"  example({\"status\": \"error\", \"error\": \"API key is required\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Текст принадлежит другому пользователю\"  example({\"status\": \"error\", \"error\": \"Forbidden\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Текст не найден или истёк\"  example({\"status\": \"error\", \"error\": \"Text not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Многофайловый текст нельзя заменить одним текстом\"  example({\"status\": \"error\", \"error\": \"Multi-file texts cannot be updated\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)\"  example({\"status\": \"error\", \"error\": \"Text has expired\", \"expires_at\": \"2026-10-19T12:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "В тексте найдены секреты\"  example({\"status\": \"error\", \"error\": \"Text contains secrets\", \"secrets\": [{\"line\": 3, \"rule\": \"jwt\"}]})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "secrets": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "file": {
+                                                "type": "string"
+                                            },
+                                            "line": {
+                                                "type": "integer"
+                                            },
+                                            "rule": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "451": {
+                        "description": "Текст скрыт или удалён модератором\"  example({\"status\": \"error\", \"error\": \"Text is unavailable\", \"reason\": \"Phishing\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "reason": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет текст из MySQL, MinIO и кэша. Доступно только владельцу текста.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "texts"
+                ],
+                "summary": "Удалить свой текст",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальный хеш текста",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Текст удалён\"  example({\"status\": \"OK\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API ключ не передан или неверен\"  example({\"status\": \"error\", \"error\": \"API key is required\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Текст принадлежит другому пользователю\"  example({\"status\": \"error\", \"error\": \"Forbidden\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Текст не найден\"  example({\"status\": \"error\", \"error\": \"Text not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)\"  example({\"status\": \"error\", \"error\": \"Text has expired\", \"expires_at\": \"2026-10-19T12:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/text/{hash}/bundle": {
+            "get": {
+                "security": [
+                    {
+                        "none": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает все файлы многофайлового текста одним архивом zip или tar. Обычный текст попадает в архив как единственный файл \u003chash\u003e.txt.",
+                "produces": [
+                    "application/zip",
+                    "application/x-tar"
+                ],
+                "tags": [
+                    "texts"
+                ],
+                "summary": "Скачать текст архивом",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальный хеш текста",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "zip",
+                            "tar"
+                        ],
+                        "type": "string",
+                        "default": "zip",
+                        "description": "Формат архива",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Архив",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестный формат\"  example({\"status\": \"error\", \"error\": \"Unknown archive format\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Текст не найден\"  example({\"status\": \"error\", \"error\": \"Text not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)\"  example({\"status\": \"error\", \"error\": \"Text has expired\", \"expires_at\": \"2026-10-19T12:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "451": {
+                        "description": "Текст скрыт или удалён модератором\"  example({\"status\": \"error\", \"error\": \"Text is unavailable\", \"reason\": \"Phishing\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "reason": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/text/{hash}/extend": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Сдвигает время истечения текста на ttl дней. Доступно только владельцу текста.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "texts"
+                ],
+                "summary": "Продлить свой текст",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальный хеш текста",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "На сколько дней продлить",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "ttl": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Новое время истечения\"  example({\"status\": \"OK\", \"expires_at\": \"2026-01-01T00:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос\"  example({\"status\": \"error\", \"error\": \"Field TTL is a required field\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API ключ не передан или неверен\"  example({\"status\": \"error\", \"error\": \"API key is required\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Текст принадлежит другому пользователю\"  example({\"status\": \"error\", \"error\": \"Forbidden\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Текст не найден или уже истёк\"  example({\"status\": \"error\", \"error\": \"Text not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)\"  example({\"status\": \"error\", \"error\": \"Text has expired\", \"expires_at\": \"2026-10-19T12:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/text/{hash}/files/{filename}": {
+            "get": {
+                "security": [
+                    {
+                        "none": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает содержимое одного файла многофайлового текста по его имени.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "texts"
+                ],
+                "summary": "Получить файл многофайлового текста",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальный хеш текста",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Имя файла",
+                        "name": "filename",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Файл\"  example({\"status\": \"OK\", \"filename\": \"app.yaml\", \"language\": \"yaml\", \"text\": \"port: 8080\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "filename": {
+                                    "type": "string"
+                                },
+                                "language": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                },
+                                "text": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Текст или файл не найдены\"  example({\"status\": \"error\", \"error\": \"File not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)\"  example({\"status\": \"error\", \"error\": \"Text has expired\", \"expires_at\": \"2026-10-19T12:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "451": {
+                        "description": "Текст скрыт или удалён модератором\"  example({\"status\": \"error\", \"error\": \"Text is unavailable\", \"reason\": \"Phishing\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "reason": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Получение не уложилось в таймаут\"  example({\"status\": \"error\", \"error\": \"Request timed out\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/text/{hash}/fork": {
+            "post": {
+                "security": [
+                    {
+                        "none": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создаёт новый текст из существующего (по умолчанию из текущей ревизии). Если text не передан или совпадает с исходным, форк использует тот же объект в MinIO. Видимость по умолчанию наследуется от исходного текста.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "texts"
+                ],
+                "summary": "Форкнуть текст",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Хеш исходного текста",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменённый текст, ревизия исходного текста, TTL и видимость форка; on_secret: reject или redact",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "on_secret": {
+                                    "type": "string"
+                                },
+                                "rev": {
+                                    "type": "integer"
+                                },
+                                "text": {
+                                    "type": "string"
+                                },
+                                "ttl": {
+                                    "type": "integer"
+                                },
+                                "visibility": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Форк создан\"  example({\"status\": \"OK\", \"hash\": \"f1e2d3\", \"parent_hash\": \"a1b2c3\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "hash": {
+                                    "type": "string"
+                                },
+                                "parent_hash": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос\"  example({\"status\": \"error\", \"error\": \"Private pastes require an API key\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный API ключ или анонимный доступ выключен\"  example({\"status\": \"error\", \"error\": \"Invalid API key\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Текст или ревизия не найдены\"  example({\"status\": \"error\", \"error\": \"Text not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)\"  example({\"status\": \"error\", \"error\": \"Text has expired\", \"expires_at\": \"2026-10-19T12:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "В тексте найдены секреты\"  example({\"status\": \"error\", \"error\": \"Text contains secrets\", \"secrets\": [{\"line\": 3, \"rule\": \"jwt\"}]})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "secrets": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "file": {
+                                                "type": "string"
+                                            },
+                                            "line": {
+                                                "type": "integer"
+                                            },
+                                            "rule": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов, см. Retry-After\"  example({\"status\": \"error\", \"error\": \"Too many requests\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "451": {
+                        "description": "Текст скрыт или удалён модератором\"  example({\"status\": \"error\", \"error\": \"Text is unavailable\", \"reason\": \"Phishing\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "reason": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Форк не уложился в таймаут\"  example({\"status\": \"error\", \"error\": \"Request timed out\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/text/{hash}/meta": {
+            "get": {
+                "security": [
+                    {
+                        "none": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает метаданные текста, список файлов (для многофайлового текста), хеш текста, из которого он форкнут, и дерево его форков. В дереве показываются публичные форки и собственные форки пользователя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "texts"
+                ],
+                "summary": "Метаданные текста",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальный хеш текста",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Метаданные текста",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "created_at": {
+                                    "type": "string"
+                                },
+                                "current_rev": {
+                                    "type": "integer"
+                                },
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "files": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "filename": {
+                                                "type": "string"
+                                            },
+                                            "language": {
+                                                "type": "string"
+                                            },
+                                            "size": {
+                                                "type": "integer"
+                                            }
+                                        }
+                                    }
+                                },
+                                "forks": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "created_at": {
+                                                "type": "string"
+                                            },
+                                            "forks": {
+                                                "type": "array",
+                                                "items": {
+                                                    "type": "object"
+                                                }
+                                            },
+                                            "hash": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                },
+                                "hash": {
+                                    "type": "string"
+                                },
+                                "language": {
+                                    "type": "string"
+                                },
+                                "language_confidence": {
+                                    "type": "number"
+                                },
+                                "parent_hash": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                },
+                                "visibility": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Текст не найден\"  example({\"status\": \"error\", \"error\": \"Text not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)\"  example({\"status\": \"error\", \"error\": \"Text has expired\", \"expires_at\": \"2026-10-19T12:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "451": {
+                        "description": "Текст скрыт или удалён модератором\"  example({\"status\": \"error\", \"error\": \"Text is unavailable\", \"reason\": \"Phishing\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "reason": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/text/{hash}/raw": {
+            "get": {
+                "security": [
+                    {
+                        "none": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отдаёт содержимое текста или загруженного файла как есть, с его MIME типом и заголовком X-Content-Type-Options: nosniff. Картинки показываются в браузере, остальные бинарные типы скачиваются вложением.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "texts"
+                ],
+                "summary": "Скачать содержимое",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальный хеш текста",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер ревизии, по умолчанию текущая",
+                        "name": "rev",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Время истечения подписанной ссылки (unix)",
+                        "name": "expires",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор ключа подписи",
+                        "name": "kid",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "HMAC подпись ссылки",
+                        "name": "sig",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Содержимое",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректная ревизия\"  example({\"status\": \"error\", \"error\": \"Invalid revision\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Подпись ссылки неверна или истекла\"  example({\"status\": \"error\", \"error\": \"Invalid or expired signature\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Текст не найден\"  example({\"status\": \"error\", \"error\": \"Text not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)\"  example({\"status\": \"error\", \"error\": \"Text has expired\", \"expires_at\": \"2026-10-19T12:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "451": {
+                        "description": "Текст скрыт или удалён модератором\"  example({\"status\": \"error\", \"error\": \"Text is unavailable\", \"reason\": \"Phishing\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "reason": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Получение не уложилось в таймаут\"  example({\"status\": \"error\", \"error\": \"Request timed out\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/text/{hash}/report": {
+            "post": {
+                "security": [
+                    {
+                        "none": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Сохраняет жалобу на текст для модераторов. От одного пользователя (или IP для анонимных читателей) учитывается одна жалоба. После нескольких жалоб от разных читателей текст скрывается до решения модератора.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "texts"
+                ],
+                "summary": "Пожаловаться на текст",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальный хеш текста",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "reason: spam, malware, phishing, abuse, illegal или other",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "comment": {
+                                    "type": "string"
+                                },
+                                "reason": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Жалоба принята\"  example({\"status\": \"OK\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос\"  example({\"status\": \"error\", \"error\": \"Field Reason is a required field\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Текст не найден или истёк\"  example({\"status\": \"error\", \"error\": \"Text not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)\"  example({\"status\": \"error\", \"error\": \"Text has expired\", \"expires_at\": \"2026-10-19T12:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов, см. Retry-After\"  example({\"status\": \"error\", \"error\": \"Too many requests\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "451": {
+                        "description": "Текст уже скрыт или удалён модератором\"  example({\"status\": \"error\", \"error\": \"Text is unavailable\", \"reason\": \"Phishing\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "reason": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/text/{hash}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "none": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает список ревизий текста по возрастанию номера.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "texts"
+                ],
+                "summary": "История ревизий",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальный хеш текста",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "История ревизий",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "hash": {
+                                    "type": "string"
+                                },
+                                "revisions": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "created_at": {
+                                                "type": "string"
+                                            },
+                                            "rev": {
+                                                "type": "integer"
+                                            },
+                                            "size": {
+                                                "type": "integer"
+                                            }
+                                        }
+                                    }
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Текст не найден\"  example({\"status\": \"error\", \"error\": \"Text not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)\"  example({\"status\": \"error\", \"error\": \"Text has expired\", \"expires_at\": \"2026-10-19T12:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "451": {
+                        "description": "Текст скрыт или удалён модератором\"  example({\"status\": \"error\", \"error\": \"Text is unavailable\", \"reason\": \"Phishing\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "reason": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/text/{hash}/share": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Выдаёт владельцу ссылку с HMAC подписью, по которой текст (в том числе приватный) можно читать без API ключа до истечения срока.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "texts"
+                ],
+                "summary": "Подписанная ссылка на текст",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальный хеш текста",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Время жизни ссылки в секундах",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "expires_in": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Ссылка создана\"  example({\"status\": \"OK\", \"url\": \"https://paste.example.com/text/a1b2c3?expires=1767225600\u0026kid=k1\u0026sig=9f..\", \"expires_at\": \"2026-01-01T00:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                },
+                                "url": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный срок действия\"  example({\"status\": \"error\", \"error\": \"Field ExpiresIn is not valid\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "API ключ не передан или неверен\"  example({\"status\": \"error\", \"error\": \"API key is required\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Текст принадлежит другому пользователю\"  example({\"status\": \"error\", \"error\": \"Forbidden\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Текст не найден\"  example({\"status\": \"error\", \"error\": \"Text not found\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)\"  example({\"status\": \"error\", \"error\": \"Text has expired\", \"expires_at\": \"2026-10-19T12:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Ключи подписи не настроены\"  example({\"status\": \"error\", \"error\": \"Signed links are disabled\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trending": {
+            "get": {
+                "security": [
+                    {
+                        "none": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает публичные тексты, которые больше всего смотрели за последнее время. window: hour — текущий и предыдущий час, day — последние 24 часа, week — последние 7 суток. Рейтинг пересчитывается раз в минуту.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "texts"
+                ],
+                "summary": "Популярное",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "day",
+                        "description": "Окно: hour, day или week",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Сколько текстов вернуть (1-50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Популярные тексты",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "pastes": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "created_at": {
+                                                "type": "string"
+                                            },
+                                            "hash": {
+                                                "type": "string"
+                                            },
+                                            "language": {
+                                                "type": "string"
+                                            },
+                                            "views": {
+                                                "type": "integer"
+                                            }
+                                        }
+                                    }
+                                },
+                                "status": {
+                                    "type": "string"
+                                },
+                                "window": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры\"  example({\"status\": \"error\", \"error\": \"Invalid window\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов, см. Retry-After\"  example({\"status\": \"error\", \"error\": \"Too many requests\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/upload": {
+            "post": {
+                "security": [
+                    {
+                        "none": []
+                    },
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
-                "description": "Сохраняет текст в хранилище и возвращает уникальный хеш для последующего доступа. Текст хранится с указанным TTL (время жизни).",
+                "description": "Сохраняет файл (например, скриншот) как текст. Тип определяется по содержимому и должен входить в список разрешённых; у каждого типа свой предел размера. Бинарное содержимое отдаётся только через GET /text/{hash}/raw.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
@@ -32,33 +4198,55 @@ const docTemplate = `{
                 "tags": [
                     "texts"
                 ],
-                "summary": "Сохранить текст",
+                "summary": "Загрузить файл",
                 "parameters": [
                     {
-                        "description": "Данные для сохранения",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                        "type": "file",
+                        "description": "Файл",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Время жизни в днях",
+                        "name": "ttl",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "public (по умолчанию), unlisted или private (только с API ключом)",
+                        "name": "visibility",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Файл сохранён\"  example({\"status\": \"OK\", \"hash\": \"a1b2c3\", \"content_type\": \"image/png\", \"size\": 48213})",
                         "schema": {
                             "type": "object",
                             "properties": {
-                                "text": {
+                                "content_type": {
                                     "type": "string"
                                 },
-                                "ttl": {
+                                "hash": {
+                                    "type": "string"
+                                },
+                                "size": {
                                     "type": "integer"
+                                },
+                                "status": {
+                                    "type": "string"
                                 }
                             }
                         }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Текст успешно сохранен\"  example({\"status\": \"ok\", \"hash\": \"a1b2c3d4e5f6\"})",
+                    },
+                    "400": {
+                        "description": "Некорректный запрос\"  example({\"status\": \"error\", \"error\": \"File is required\"})",
                         "schema": {
                             "type": "object",
                             "properties": {
-                                "hash": {
+                                "error": {
                                     "type": "string"
                                 },
                                 "status": {
@@ -67,8 +4255,81 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Некорректный запрос\"  example({\"status\": \"error\", \"error\": \"Text is required\"})",
+                    "401": {
+                        "description": "Неверный API ключ или анонимный доступ выключен\"  example({\"status\": \"error\", \"error\": \"Invalid API key\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой\"  example({\"status\": \"error\", \"error\": \"File is too large\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Тип файла не разрешён\"  example({\"status\": \"error\", \"error\": \"Unsupported content type application/zip\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "В тексте найдены секреты\"  example({\"status\": \"error\", \"error\": \"Text contains secrets\", \"secrets\": [{\"line\": 3, \"rule\": \"jwt\"}]})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "secrets": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "file": {
+                                                "type": "string"
+                                            },
+                                            "line": {
+                                                "type": "integer"
+                                            },
+                                            "rule": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов, см. Retry-After\"  example({\"status\": \"error\", \"error\": \"Too many requests\"})",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -82,7 +4343,21 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Failed to save text\"})",
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Сохранение не уложилось в таймаут\"  example({\"status\": \"error\", \"error\": \"Request timed out\"})",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -95,57 +4370,96 @@ const docTemplate = `{
                             }
                         }
                     }
-                },
-                "x-order": 1
+                }
             }
         },
-        "/text/{hash}": {
+        "/view/{hash}": {
             "get": {
                 "security": [
                     {
                         "none": []
+                    },
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
-                "description": "Получает сохраненный текст по его уникальному хешу. Популярные тексты кэшируются в Redis для быстрого доступа.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает HTML страницу с подсвеченным текстом. Язык берётся из параметра lang, из сохранённого при создании или определяется по содержимому. Номера строк — ссылки-якоря вида #L10. HTML популярных текстов кэшируется в Redis.",
                 "produces": [
-                    "application/json"
+                    "text/html"
                 ],
                 "tags": [
                     "texts"
                 ],
-                "summary": "Получить текст",
+                "summary": "Посмотреть текст с подсветкой",
                 "parameters": [
                     {
-                        "maxLength": 64,
-                        "minLength": 6,
                         "type": "string",
-                        "example": "a1b2c3d4e5f6",
-                        "description": "Уникальный хеш текста (буквенно-цифровая строка)",
+                        "description": "Уникальный хеш текста",
                         "name": "hash",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тема оформления chroma (github, monokai, dracula, ...)",
+                        "name": "theme",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Показывать номера строк",
+                        "name": "lines",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык подсветки, по умолчанию определяется автоматически",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Время истечения подписанной ссылки (unix)",
+                        "name": "expires",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор ключа подписи",
+                        "name": "kid",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "HMAC подпись ссылки",
+                        "name": "sig",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Текст успешно получен\"  example({\"status\": \"ok\", \"text\": \"Hello, World!\"})",
+                        "description": "HTML страница",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестная тема или язык\"  example({\"status\": \"error\", \"error\": \"Unknown theme\"})",
                         "schema": {
                             "type": "object",
                             "properties": {
-                                "status": {
+                                "error": {
                                     "type": "string"
                                 },
-                                "text": {
+                                "status": {
                                     "type": "string"
                                 }
                             }
                         }
                     },
-                    "400": {
-                        "description": "Хеш не указан\"  example({\"status\": \"error\", \"error\": \"Hash is empty\"})",
+                    "403": {
+                        "description": "Подпись ссылки неверна или истекла\"  example({\"status\": \"error\", \"error\": \"Invalid or expired signature\"})",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -172,8 +4486,84 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "410": {
+                        "description": "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)\"  example({\"status\": \"error\", \"error\": \"Text has expired\", \"expires_at\": \"2026-10-19T12:00:00Z\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "expires_at": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Текст слишком большой для подсветки\"  example({\"status\": \"error\", \"error\": \"Text is too large to highlight\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Содержимое бинарное\"  example({\"status\": \"error\", \"error\": \"Binary content can't be highlighted\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "451": {
+                        "description": "Текст скрыт или удалён модератором\"  example({\"status\": \"error\", \"error\": \"Text is unavailable\", \"reason\": \"Phishing\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "reason": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
                     "500": {
-                        "description": "Ошибка при получении текста\"  example({\"status\": \"error\", \"error\": \"Failed to get text\"})",
+                        "description": "Внутренняя ошибка сервера\"  example({\"status\": \"error\", \"error\": \"Internal error\"})",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Получение не уложилось в таймаут\"  example({\"status\": \"error\", \"error\": \"Request timed out\"})",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -186,10 +4576,17 @@ const docTemplate = `{
                             }
                         }
                     }
-                },
-                "x-order": 2
+                }
             }
         }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "API ключ, выданный командой apikey. Также принимается как Authorization: Bearer \u003ckey\u003e",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        }
     }
 }`
 
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"time"
@@ -9,14 +10,29 @@ import (
 )
 
 type Config struct {
-	Env        string `yaml:"env" env-default:"local"`
-	DefaultTTL int    `yaml:"default_ttl" env-default:"1"`
-	HTTPServer `yaml:"http_server"`
-	MySQL      `yaml:"mysql"`
-	Kafka      `yaml:"kafka"`
-	MinIO      `yaml:"minio"`
-	Redis      `yaml:"redis"`
-	Swagger    `yaml:"swagger"`
+	Env         string `yaml:"env" env-default:"local"`
+	DefaultTTL  int    `yaml:"default_ttl" env-default:"1"`
+	HTTPServer  `yaml:"http_server"`
+	MySQL       `yaml:"mysql"`
+	Kafka       `yaml:"kafka"`
+	MinIO       `yaml:"minio"`
+	Redis       `yaml:"redis"`
+	LocalCache  `yaml:"local_cache"`
+	Swagger     `yaml:"swagger"`
+	Tracing     `yaml:"tracing"`
+	Health      `yaml:"health"`
+	Timeouts    `yaml:"timeouts"`
+	HTTPCache   `yaml:"http_cache"`
+	RateLimit   `yaml:"rate_limit"`
+	Auth        `yaml:"auth"`
+	Signing     `yaml:"signing"`
+	Enumeration `yaml:"anti_enumeration"`
+	Diff        `yaml:"diff"`
+	Uploads     `yaml:"uploads"`
+	View        `yaml:"view"`
+	Secrets     `yaml:"secrets"`
+	Moderation  `yaml:"moderation"`
+	Admin       `yaml:"admin"`
 }
 
 type HTTPServer struct {
@@ -25,12 +41,149 @@ type HTTPServer struct {
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
 }
 
+// * Timeouts — дедлайны на операции с текстом, отсчитываются от начала запроса.
+// * Save применяется ко всем изменяющим операциям, Get — к чтению.
+type Timeouts struct {
+	Save time.Duration `yaml:"save" env-default:"3s"`
+	Get  time.Duration `yaml:"get" env-default:"2s"`
+}
+
+// * булевы флаги по умолчанию false: cleanenv подставляет env-default поверх нулевого значения из yaml,
+// * поэтому флаг с default "true" нельзя выключить в конфиге
+type Auth struct {
+	RequireAPIKey bool `yaml:"require_api_key" env-default:"false"` // * запретить запросы к текстам без API ключа
+}
+
+// * Signing — ключи HMAC для подписанных ссылок. Без ключей подпись выключена.
+// * Для ротации добавьте новый ключ в keys, переключите active_key
+// * и удалите старый ключ после max_ttl, когда выданные им ссылки истекут.
+type Signing struct {
+	ActiveKey  string            `yaml:"active_key" env-default:""`
+	Keys       map[string]string `yaml:"keys"`
+	BaseURL    string            `yaml:"base_url" env-default:""`
+	DefaultTTL time.Duration     `yaml:"default_ttl" env-default:"1h"`
+	MaxTTL     time.Duration     `yaml:"max_ttl" env-default:"168h"`
+}
+
+// * Enumeration — защита GET /text/{hash} от перебора хэшей
+type Enumeration struct {
+	Enabled            bool          `yaml:"enabled" env-default:"false"`
+	Window             time.Duration `yaml:"window" env-default:"1m"`
+	MaxMisses          int           `yaml:"max_misses" env-default:"30"`
+	BaseBan            time.Duration `yaml:"base_ban" env-default:"1m"`
+	MaxBan             time.Duration `yaml:"max_ban" env-default:"24h"`
+	MissCacheTTL       time.Duration `yaml:"miss_cache_ttl" env-default:"1m"`
+	UniformNotFound    bool          `yaml:"uniform_not_found" env-default:"false"`
+	NotFoundMinLatency time.Duration `yaml:"not_found_min_latency" env-default:"50ms"`
+}
+
+// * Diff — ограничения GET /diff/{hashA}/{hashB}
+type Diff struct {
+	MaxSize        int `yaml:"max_size" env-default:"262144"` // * предельный размер каждого текста в байтах
+	DefaultContext int `yaml:"default_context" env-default:"3"`
+	MaxContext     int `yaml:"max_context" env-default:"100"`
+}
+
+// * View — HTML страница GET /view/{hash} с подсветкой синтаксиса
+type View struct {
+	DefaultTheme string `yaml:"default_theme" env-default:"github"`
+	MaxSize      int    `yaml:"max_size" env-default:"1048576"` // * больше не подсвечиваем, слишком долго
+}
+
+// * Secrets — поиск ключей и токенов в сохраняемых текстах.
+// * Mode — действие по умолчанию, запрос может выбрать reject или redact сам, но не выключить проверку.
+type Secrets struct {
+	Mode          string       `yaml:"mode" env-default:"reject"` // * off | reject | redact
+	DisabledRules []string     `yaml:"disabled_rules"`            // * имена встроенных правил, которые не применять
+	Rules         []SecretRule `yaml:"rules"`                     // * дополнительные правила
+}
+
+// * SecretRule — дополнительное правило поиска секретов
+type SecretRule struct {
+	Name       string  `yaml:"name"`
+	Pattern    string  `yaml:"pattern"`
+	Group      int     `yaml:"group"`       // * группа выражения с самим секретом, 0 — всё совпадение
+	MinEntropy float64 `yaml:"min_entropy"` // * минимальная энтропия секрета в битах на символ
+}
+
+// * Moderation — жалобы читателей на тексты
+type Moderation struct {
+	AutoHideReports int `yaml:"auto_hide_reports" env-default:"5"` // * скрыть текст после стольких жалоб от разных читателей; 0 — не скрывать
+}
+
+// * Admin — API администратора /admin
+type Admin struct {
+	JobTimeout time.Duration `yaml:"job_timeout" env-default:"30m"` // * предел длительности cleanup и reconcile, запущенных вручную
+}
+
+// * Uploads — загрузка файлов через POST /upload.
+// * Тип файла определяется по содержимому, заявленный клиентом Content-Type не учитывается.
+type Uploads struct {
+	MaxSize int64            `yaml:"max_size" env-default:"10485760"` // * предел тела запроса в байтах
+	Types   map[string]int64 `yaml:"types"`                           // * разрешённые MIME типы и предельный размер для каждого
+}
+
+type RateLimit struct {
+	Enabled    bool  `yaml:"enabled" env-default:"false"`
+	TrustProxy bool  `yaml:"trust_proxy" env-default:"false"` // * брать IP клиента из X-Forwarded-For / X-Real-IP
+	IP         Limit `yaml:"ip"`                              // * общий лимит на IP до проверки API ключа, в том числе неверного
+	Save       Limit `yaml:"save"`
+	Get        Limit `yaml:"get"`
+}
+
+// * Limit — параметры token bucket
+type Limit struct {
+	Rate  float64 `yaml:"rate" env-default:"1"`   // * токенов в секунду
+	Burst int     `yaml:"burst" env-default:"10"` // * ёмкость bucket
+}
+
+// * Validate проверяет, что bucket пополняется и вмещает хотя бы один запрос:
+// * скорость делит и заголовки RateLimit-Policy, и скрипт bucket в redis
+func (l Limit) Validate() error {
+	if !(l.Rate > 0) {
+		return fmt.Errorf("rate must be positive, got %v", l.Rate)
+	}
+
+	if l.Burst < 1 {
+		return fmt.Errorf("burst must be at least 1, got %d", l.Burst)
+	}
+
+	return nil
+}
+
+// * validate проверяет значения, которые нельзя выразить тегами cleanenv
+func (c *Config) validate() error {
+	if c.RateLimit.Enabled {
+		for name, l := range map[string]Limit{"ip": c.RateLimit.IP, "save": c.RateLimit.Save, "get": c.RateLimit.Get} {
+			if err := l.Validate(); err != nil {
+				return fmt.Errorf("rate_limit.%s: %w", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+type Health struct {
+	Timeout    time.Duration `yaml:"timeout" env-default:"1s"`
+	DrainDelay time.Duration `yaml:"drain_delay" env-default:"5s"`
+}
+
 type Swagger struct {
 	Username string `yaml:"username" env-default:"admin"`
 	Password string `yaml:"password" env-default:"admin"`
 	Enabled  bool   `yaml:"enabled" env-default:"false"`
 }
 
+type Tracing struct {
+	Enabled     bool    `yaml:"enabled" env-default:"false"`
+	Exporter    string  `yaml:"exporter" env-default:"stdout"` // otlp | stdout
+	Endpoint    string  `yaml:"endpoint" env-default:"localhost:4318"`
+	Insecure    bool    `yaml:"insecure" env-default:"false"`
+	ServiceName string  `yaml:"service_name" env-default:"main_service"`
+	SampleRatio float64 `yaml:"sample_ratio" env-default:"1"`
+}
+
 type MinIO struct {
 	Endpoint string `yaml:"endpoint" env-default:"localhost:9000"`
 	User     string `yaml:"user" env-required:"true"`
@@ -52,6 +205,21 @@ type Redis struct {
 	Addr                string `yaml:"addr" env-default:"redis:6379"`
 	Db                  int    `yaml:"db" env-default:"1"`
 	PopularityThreshold int64  `yaml:"popularity_threshold" env-default:"500"`
+	// * максимальное время жизни текста в кэше; меньше, если текст истекает раньше
+	CacheTTL time.Duration `yaml:"cache_ttl" env-default:"24h"`
+}
+
+// * HTTPCache — заголовки кэширования ответа GET /text/{hash}
+type HTTPCache struct {
+	MaxAge time.Duration `yaml:"max_age" env-default:"1h"` // * предел max-age, даже если текст живёт дольше
+}
+
+// * LocalCache — L1 кэш популярных текстов в памяти каждой реплики перед redis
+type LocalCache struct {
+	Enabled    bool          `yaml:"enabled" env-default:"false"`
+	MaxBytes   int64         `yaml:"max_bytes" env-default:"67108864"`
+	TTL        time.Duration `yaml:"ttl" env-default:"10s"`        // * сколько живёт запись, даже если инвалидация не дошла
+	FlushEvery time.Duration `yaml:"flush_every" env-default:"5s"` // * как часто отправлять накопленные просмотры в redis
 }
 
 func MustLoad(configPath string) *Config {
@@ -66,5 +234,9 @@ func MustLoad(configPath string) *Config {
 		log.Fatalf("cannot read config: %s", configPath)
 	}
 
+	if err := cfg.validate(); err != nil {
+		log.Fatalf("invalid config %s: %s", configPath, err)
+	}
+
 	return &cfg
 }
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLimitValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit Limit
+		ok    bool
+	}{
+		{name: "valid", limit: Limit{Rate: 0.5, Burst: 1}, ok: true},
+		{name: "zero rate", limit: Limit{Rate: 0, Burst: 10}},
+		{name: "negative rate", limit: Limit{Rate: -1, Burst: 10}},
+		{name: "NaN rate", limit: Limit{Rate: math.NaN(), Burst: 10}},
+		{name: "zero burst", limit: Limit{Rate: 1, Burst: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.limit.Validate(); (err == nil) != tt.ok {
+				t.Errorf("Validate() error = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestValidateRateLimit(t *testing.T) {
+	valid := Limit{Rate: 1, Burst: 10}
+
+	cfg := Config{RateLimit: RateLimit{Enabled: true, IP: valid, Save: valid, Get: Limit{Rate: 0, Burst: 10}}}
+	if err := cfg.validate(); err == nil {
+		t.Error("validate() accepted a zero get rate")
+	}
+
+	// Выключенный лимит не проверяется
+	cfg.RateLimit.Enabled = false
+	if err := cfg.validate(); err != nil {
+		t.Errorf("validate() error = %v for disabled rate limit", err)
+	}
+}
@@ -0,0 +1,75 @@
+package dismiss
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"main_service/internal/lib/api/notfound"
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+// New godoc
+// @Summary      Отклонить жалобы
+// @Description  Удаляет жалобы на текст и возвращает автоматически скрытый текст читателям. Удалённый текст восстановить нельзя. Только для администраторов.
+// @Tags         admin
+// @Produce      json
+// @Param        hash  path  string  true  "Уникальный хеш текста"
+// @Success      200  {object}  object{status=string}  "Жалобы отклонены"  example({"status": "OK"})
+// @Failure      401  {object}  object{status=string,error=string}  "API ключ не передан или неверен"  example({"status": "error", "error": "API key is required"})
+// @Failure      403  {object}  object{status=string,error=string}  "Пользователь не администратор"  example({"status": "error", "error": "Forbidden"})
+// @Failure      404  {object}  object{status=string,error=string}  "Текст не найден или истёк"  example({"status": "error", "error": "Text not found"})
+// @Failure      410  {object}  object{status=string,error=string,expires_at=string}  "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)"  example({"status": "error", "error": "Text has expired", "expires_at": "2026-10-19T12:00:00Z"})
+// @Failure      409  {object}  object{status=string,error=string}  "Текст уже удалён модератором"  example({"status": "error", "error": "Text is already taken down"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Router       /admin/reports/{hash}/dismiss [post]
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, moderator models.Moderator, timeout time.Duration, notFound notfound.Policy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.admin.dismiss.New"
+
+		start := time.Now()
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hash := chi.URLParam(r, "hash")
+		admin, _ := auth.UserFromContext(r.Context())
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		if err := moderator.DismissReports(ctx, hash); err != nil {
+			switch {
+			case errors.Is(err, storage.ErrTakenDown):
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, resp.Error("Text is already taken down"))
+			case notfound.Match(err):
+				notFound.Respond(w, r, err, start)
+			default:
+				log.Error("failed to dismiss reports", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("Internal error"))
+			}
+
+			return
+		}
+
+		log.Info("Reports dismissed", slog.String("hash", hash), slog.Int64("admin_id", admin.ID))
+
+		render.JSON(w, r, resp.OK())
+	}
+}
@@ -0,0 +1,66 @@
+package expire
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+// New godoc
+// @Summary      Истечь текст
+// @Description  Сразу делает текст истёкшим и убирает его из кэша; данные удалит ближайшая очистка. Только для администраторов.
+// @Tags         admin
+// @Produce      json
+// @Param        hash  path  string  true  "Уникальный хеш текста"
+// @Success      200  {object}  object{status=string}  "Текст истёк"  example({"status": "OK"})
+// @Failure      401  {object}  object{status=string,error=string}  "API ключ не передан или неверен"  example({"status": "error", "error": "API key is required"})
+// @Failure      403  {object}  object{status=string,error=string}  "Пользователь не администратор"  example({"status": "error", "error": "Forbidden"})
+// @Failure      404  {object}  object{status=string,error=string}  "Текст не найден"  example({"status": "error", "error": "Text not found"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Router       /admin/pastes/{hash}/expire [post]
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, admin models.Admin, timeout time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.admin.expire.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hash := chi.URLParam(r, "hash")
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		if err := admin.ExpireText(ctx, hash); err != nil {
+			switch {
+			case errors.Is(err, storage.ErrTextNotFound):
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Text not found"))
+			default:
+				log.Error("failed to expire text", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("Internal error"))
+			}
+
+			return
+		}
+
+		log.Info("Text expired by admin", slog.String("hash", hash))
+
+		render.JSON(w, r, resp.OK())
+	}
+}
@@ -0,0 +1,103 @@
+package extend
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type Request struct {
+	TTL int `json:"ttl" validate:"required,min=1"`
+}
+
+type Response struct {
+	resp.Response
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// New godoc
+// @Summary      Продлить текст
+// @Description  Сдвигает время истечения любого текста на ttl дней. Истёкший, но ещё не удалённый текст продлевается от текущего момента. Только для администраторов.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        hash     path  string           true  "Уникальный хеш текста"
+// @Param        request  body  object{ttl=int}  true  "На сколько дней продлить"  example({"ttl": 7})
+// @Success      200  {object}  object{status=string,expires_at=string}  "Новое время истечения"  example({"status": "OK", "expires_at": "2026-10-26T12:00:00Z"})
+// @Failure      400  {object}  object{status=string,error=string}  "Некорректный запрос"  example({"status": "error", "error": "Field TTL is a required field"})
+// @Failure      401  {object}  object{status=string,error=string}  "API ключ не передан или неверен"  example({"status": "error", "error": "API key is required"})
+// @Failure      403  {object}  object{status=string,error=string}  "Пользователь не администратор"  example({"status": "error", "error": "Forbidden"})
+// @Failure      404  {object}  object{status=string,error=string}  "Текст не найден"  example({"status": "error", "error": "Text not found"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Router       /admin/pastes/{hash}/extend [post]
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, admin models.Admin, timeout time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.admin.extend.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hash := chi.URLParam(r, "hash")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("Failed to decode request body", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		expiresAt, err := admin.ExtendText(ctx, hash, req.TTL)
+		if err != nil {
+			switch {
+			case errors.Is(err, storage.ErrTextNotFound):
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Text not found"))
+			default:
+				log.Error("failed to extend text", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("Internal error"))
+			}
+
+			return
+		}
+
+		log.Info("Text extended by admin", slog.String("hash", hash), slog.Time("expires_at", expiresAt))
+
+		render.JSON(w, r, Response{
+			Response:  resp.OK(),
+			ExpiresAt: expiresAt,
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+// * Jobs — фоновые задачи обслуживания; обе возвращают число удалённых объектов
+type Jobs interface {
+	Run(ctx context.Context) (int, error)
+	Reconcile(ctx context.Context) (int, error)
+	Busy() bool
+}
+
+// New godoc
+// @Summary      Запустить задачу обслуживания
+// @Description  Запускает в фоне cleanup (удаление истёкших текстов, обычно раз в сутки) или reconcile (удаление из MinIO объектов, на которые не ссылается ни один текст). Результат пишется в лог. Только для администраторов.
+// @Tags         admin
+// @Produce      json
+// @Param        job  path  string  true  "cleanup или reconcile"
+// @Success      202  {object}  object{status=string}  "Задача запущена"  example({"status": "OK"})
+// @Failure      401  {object}  object{status=string,error=string}  "API ключ не передан или неверен"  example({"status": "error", "error": "API key is required"})
+// @Failure      403  {object}  object{status=string,error=string}  "Пользователь не администратор"  example({"status": "error", "error": "Forbidden"})
+// @Failure      404  {object}  object{status=string,error=string}  "Неизвестная задача"  example({"status": "error", "error": "Unknown job"})
+// @Failure      409  {object}  object{status=string,error=string}  "Задача уже выполняется"  example({"status": "error", "error": "Job is already running"})
+// @Router       /admin/jobs/{job} [post]
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, jobs Jobs, timeout time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.admin.jobs.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		name := chi.URLParam(r, "job")
+
+		var job func(ctx context.Context) (int, error)
+		switch name {
+		case "cleanup":
+			job = jobs.Run
+		case "reconcile":
+			job = jobs.Reconcile
+		default:
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, resp.Error("Unknown job"))
+
+			return
+		}
+
+		if jobs.Busy() {
+			render.Status(r, http.StatusConflict)
+			render.JSON(w, r, resp.Error("Job is already running"))
+
+			return
+		}
+
+		// Задача дольше любого HTTP таймаута, поэтому не привязана к запросу
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			n, err := job(ctx)
+			if err != nil {
+				log.Error("job failed", slog.String("job", name), sl.Err(err))
+				return
+			}
+
+			log.Info("job completed", slog.String("job", name), slog.Int("deleted", n))
+		}()
+
+		render.Status(r, http.StatusAccepted)
+		render.JSON(w, r, resp.OK())
+	}
+}
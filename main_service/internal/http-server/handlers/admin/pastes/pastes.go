@@ -0,0 +1,198 @@
+package pastes
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/models"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 500
+)
+
+type Paste struct {
+	Hash        string    `json:"hash"`
+	OwnerID     int64     `json:"owner_id,omitempty"`
+	Visibility  string    `json:"visibility"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Language    string    `json:"language,omitempty"`
+	Moderation  string    `json:"moderation,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type Response struct {
+	resp.Response
+	Pastes []Paste `json:"pastes"`
+	Total  int     `json:"total"`
+	Limit  int     `json:"limit"`
+	Offset int     `json:"offset"`
+}
+
+// New godoc
+// @Summary      Список текстов
+// @Description  Возвращает тексты всех пользователей по фильтрам, новые первыми. Время — в RFC 3339, размер — размер текущей ревизии в байтах. Истёкшие, но ещё не удалённые тексты показываются только с include_expired. Только для администраторов.
+// @Tags         admin
+// @Produce      json
+// @Param        created_from     query  string  false  "Созданы не раньше"  example(2026-10-01T00:00:00Z)
+// @Param        created_to       query  string  false  "Созданы раньше"
+// @Param        expires_from     query  string  false  "Истекают не раньше"
+// @Param        expires_to       query  string  false  "Истекают раньше"
+// @Param        min_size         query  int     false  "Минимальный размер"
+// @Param        max_size         query  int     false  "Максимальный размер"
+// @Param        owner_id         query  int     false  "Владелец"
+// @Param        include_expired  query  bool    false  "Показывать истёкшие"
+// @Param        limit            query  int     false  "Размер страницы (1-500)"  default(50)
+// @Param        offset           query  int     false  "Смещение"  default(0)
+// @Success      200  {object}  object{status=string,pastes=[]object{hash=string,owner_id=int,visibility=string,content_type=string,size=int,language=string,moderation=string,created_at=string,expires_at=string},total=int,limit=int,offset=int}  "Страница текстов"
+// @Failure      400  {object}  object{status=string,error=string}  "Некорректный фильтр"  example({"status": "error", "error": "Invalid created_from"})
+// @Failure      401  {object}  object{status=string,error=string}  "API ключ не передан или неверен"  example({"status": "error", "error": "API key is required"})
+// @Failure      403  {object}  object{status=string,error=string}  "Пользователь не администратор"  example({"status": "error", "error": "Forbidden"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Router       /admin/pastes [get]
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, admin models.Admin, timeout time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.admin.pastes.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		f, bad := parseFilter(r)
+		if bad != "" {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid "+bad))
+
+			return
+		}
+
+		limit, ok := queryInt(r, "limit", defaultLimit)
+		if !ok || limit < 1 || limit > maxLimit {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid limit"))
+
+			return
+		}
+
+		offset, ok := queryInt(r, "offset", 0)
+		if !ok || offset < 0 {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid offset"))
+
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		list, total, err := admin.ListPastes(ctx, f, limit, offset)
+		if err != nil {
+			log.Error("failed to list pastes", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("Internal error"))
+
+			return
+		}
+
+		pastes := make([]Paste, 0, len(list))
+		for _, p := range list {
+			pastes = append(pastes, Paste{
+				Hash:        p.Hash,
+				OwnerID:     p.OwnerID,
+				Visibility:  p.Visibility,
+				ContentType: p.ContentType,
+				Size:        p.Size,
+				Language:    p.Language,
+				Moderation:  p.Moderation,
+				CreatedAt:   p.CreatedAt,
+				ExpiresAt:   p.ExpiresAt,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Pastes:   pastes,
+			Total:    total,
+			Limit:    limit,
+			Offset:   offset,
+		})
+	}
+}
+
+// * parseFilter разбирает фильтры из query; при ошибке возвращает имя неверного параметра
+func parseFilter(r *http.Request) (models.PasteFilter, string) {
+	var f models.PasteFilter
+
+	for name, dst := range map[string]*time.Time{
+		"created_from": &f.CreatedFrom,
+		"created_to":   &f.CreatedTo,
+		"expires_from": &f.ExpiresFrom,
+		"expires_to":   &f.ExpiresTo,
+	} {
+		raw := r.URL.Query().Get(name)
+		if raw == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return f, name
+		}
+		*dst = t.UTC()
+	}
+
+	for name, dst := range map[string]*int64{
+		"min_size": &f.MinSize,
+		"max_size": &f.MaxSize,
+		"owner_id": &f.OwnerID,
+	} {
+		raw := r.URL.Query().Get(name)
+		if raw == "" {
+			continue
+		}
+
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || v < 0 {
+			return f, name
+		}
+		*dst = v
+	}
+
+	if raw := r.URL.Query().Get("include_expired"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return f, "include_expired"
+		}
+		f.IncludeExpired = v
+	}
+
+	return f, ""
+}
+
+func queryInt(r *http.Request, name string, def int) (int, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, true
+	}
+
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, false
+	}
+
+	return v, true
+}
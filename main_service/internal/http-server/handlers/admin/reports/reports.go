@@ -0,0 +1,130 @@
+package reports
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/models"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+type Paste struct {
+	Hash           string    `json:"hash"`
+	OwnerID        int64     `json:"owner_id,omitempty"`
+	Visibility     string    `json:"visibility"`
+	Moderation     string    `json:"moderation,omitempty"`
+	Reports        int       `json:"reports"`
+	LastReportedAt time.Time `json:"last_reported_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+type Response struct {
+	resp.Response
+	Pastes []Paste `json:"pastes"`
+	Total  int     `json:"total"`
+	Limit  int     `json:"limit"`
+	Offset int     `json:"offset"`
+}
+
+// New godoc
+// @Summary      Очередь модерации
+// @Description  Возвращает тексты с жалобами, кроме уже удалённых: сначала автоматически скрытые, затем по числу жалоб. Только для администраторов.
+// @Tags         admin
+// @Produce      json
+// @Param        limit   query  int  false  "Размер страницы (1-100)"  default(20)
+// @Param        offset  query  int  false  "Смещение"  default(0)
+// @Success      200  {object}  object{status=string,pastes=[]object{hash=string,owner_id=int,visibility=string,moderation=string,reports=int,last_reported_at=string,created_at=string,expires_at=string},total=int,limit=int,offset=int}  "Страница очереди"
+// @Failure      400  {object}  object{status=string,error=string}  "Некорректные параметры пагинации"  example({"status": "error", "error": "Invalid limit"})
+// @Failure      401  {object}  object{status=string,error=string}  "API ключ не передан или неверен"  example({"status": "error", "error": "API key is required"})
+// @Failure      403  {object}  object{status=string,error=string}  "Пользователь не администратор"  example({"status": "error", "error": "Forbidden"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Router       /admin/reports [get]
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, moderator models.Moderator, timeout time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.admin.reports.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		limit, ok := queryInt(r, "limit", defaultLimit)
+		if !ok || limit < 1 || limit > maxLimit {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid limit"))
+
+			return
+		}
+
+		offset, ok := queryInt(r, "offset", 0)
+		if !ok || offset < 0 {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid offset"))
+
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		list, total, err := moderator.ReportedPastes(ctx, limit, offset)
+		if err != nil {
+			log.Error("failed to list reported pastes", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("Internal error"))
+
+			return
+		}
+
+		pastes := make([]Paste, 0, len(list))
+		for _, p := range list {
+			pastes = append(pastes, Paste{
+				Hash:           p.Hash,
+				OwnerID:        p.OwnerID,
+				Visibility:     p.Visibility,
+				Moderation:     p.Moderation,
+				Reports:        p.Reports,
+				LastReportedAt: p.LastReportedAt,
+				CreatedAt:      p.CreatedAt,
+				ExpiresAt:      p.ExpiresAt,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Pastes:   pastes,
+			Total:    total,
+			Limit:    limit,
+			Offset:   offset,
+		})
+	}
+}
+
+func queryInt(r *http.Request, name string, def int) (int, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, true
+	}
+
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, false
+	}
+
+	return v, true
+}
@@ -0,0 +1,115 @@
+package review
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"main_service/internal/lib/api/notfound"
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/models"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+type Report struct {
+	Reason     string    `json:"reason"`
+	Comment    string    `json:"comment,omitempty"`
+	ReporterID int64     `json:"reporter_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type Response struct {
+	resp.Response
+	Hash             string    `json:"hash"`
+	OwnerID          int64     `json:"owner_id,omitempty"`
+	Visibility       string    `json:"visibility"`
+	Moderation       string    `json:"moderation,omitempty"`
+	ModerationReason string    `json:"moderation_reason,omitempty"`
+	ContentType      string    `json:"content_type,omitempty"`
+	Language         string    `json:"language,omitempty"`
+	Text             string    `json:"text,omitempty"` // * только для текстового содержимого
+	Reports          []Report  `json:"reports"`
+	CreatedAt        time.Time `json:"created_at"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+// New godoc
+// @Summary      Текст на модерации
+// @Description  Возвращает текст вместе с жалобами на него, в том числе скрытый. Бинарное содержимое не включается в ответ; у удалённого текста содержимого нет. Только для администраторов.
+// @Tags         admin
+// @Produce      json
+// @Param        hash  path  string  true  "Уникальный хеш текста"
+// @Success      200  {object}  object{status=string,hash=string,owner_id=int,visibility=string,moderation=string,moderation_reason=string,content_type=string,language=string,text=string,reports=[]object{reason=string,comment=string,reporter_id=int,created_at=string},created_at=string,expires_at=string}  "Текст и жалобы"
+// @Failure      401  {object}  object{status=string,error=string}  "API ключ не передан или неверен"  example({"status": "error", "error": "API key is required"})
+// @Failure      403  {object}  object{status=string,error=string}  "Пользователь не администратор"  example({"status": "error", "error": "Forbidden"})
+// @Failure      404  {object}  object{status=string,error=string}  "Текст не найден или истёк"  example({"status": "error", "error": "Text not found"})
+// @Failure      410  {object}  object{status=string,error=string,expires_at=string}  "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)"  example({"status": "error", "error": "Text has expired", "expires_at": "2026-10-19T12:00:00Z"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Router       /admin/reports/{hash} [get]
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, moderator models.Moderator, timeout time.Duration, notFound notfound.Policy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.admin.review.New"
+
+		start := time.Now()
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hash := chi.URLParam(r, "hash")
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		paste, reports, content, err := moderator.ReviewPaste(ctx, hash)
+		if err != nil {
+			switch {
+			case notfound.Match(err):
+				notFound.Respond(w, r, err, start)
+			default:
+				log.Error("failed to review text", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("Internal error"))
+			}
+
+			return
+		}
+
+		res := Response{
+			Response:         resp.OK(),
+			Hash:             paste.Hash,
+			OwnerID:          paste.OwnerID,
+			Visibility:       paste.Visibility,
+			Moderation:       paste.Moderation,
+			ModerationReason: paste.ModerationReason,
+			Language:         paste.Language,
+			Reports:          make([]Report, 0, len(reports)),
+			CreatedAt:        paste.CreatedAt,
+			ExpiresAt:        paste.ExpiresAt,
+		}
+		if content != nil {
+			res.ContentType = content.ContentType
+			if models.IsText(content.ContentType) {
+				res.Text = content.Data
+			}
+		}
+		for _, rep := range reports {
+			res.Reports = append(res.Reports, Report{
+				Reason:     rep.Reason,
+				Comment:    rep.Comment,
+				ReporterID: rep.ReporterID,
+				CreatedAt:  rep.CreatedAt,
+			})
+		}
+
+		render.JSON(w, r, res)
+	}
+}
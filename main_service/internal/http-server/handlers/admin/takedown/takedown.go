@@ -0,0 +1,97 @@
+package takedown
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"main_service/internal/lib/api/notfound"
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type Request struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+// New godoc
+// @Summary      Удалить текст по жалобе
+// @Description  Удаляет содержимое текста из MySQL, MinIO и кэша, оставляя метаданные: дальше запросы к тексту получают 451 с указанной причиной. Только для администраторов.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        hash     path  string                true  "Уникальный хеш текста"
+// @Param        request  body  object{reason=string}  true  "Причина, которую увидят читатели"  example({"reason": "Phishing"})
+// @Success      200  {object}  object{status=string}  "Текст удалён"  example({"status": "OK"})
+// @Failure      400  {object}  object{status=string,error=string}  "Некорректный запрос"  example({"status": "error", "error": "Field Reason is a required field"})
+// @Failure      401  {object}  object{status=string,error=string}  "API ключ не передан или неверен"  example({"status": "error", "error": "API key is required"})
+// @Failure      403  {object}  object{status=string,error=string}  "Пользователь не администратор"  example({"status": "error", "error": "Forbidden"})
+// @Failure      404  {object}  object{status=string,error=string}  "Текст не найден или истёк"  example({"status": "error", "error": "Text not found"})
+// @Failure      410  {object}  object{status=string,error=string,expires_at=string}  "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)"  example({"status": "error", "error": "Text has expired", "expires_at": "2026-10-19T12:00:00Z"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Router       /admin/reports/{hash}/takedown [post]
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, moderator models.Moderator, timeout time.Duration, notFound notfound.Policy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.admin.takedown.New"
+
+		start := time.Now()
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hash := chi.URLParam(r, "hash")
+		admin, _ := auth.UserFromContext(r.Context())
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("Failed to decode request body", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		if err := moderator.TakeDown(ctx, hash, req.Reason); err != nil {
+			switch {
+			case notfound.Match(err):
+				notFound.Respond(w, r, err, start)
+			default:
+				log.Error("failed to take down text", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("Internal error"))
+			}
+
+			return
+		}
+
+		log.Info("Text taken down", slog.String("hash", hash), slog.Int64("admin_id", admin.ID))
+
+		render.JSON(w, r, resp.OK())
+	}
+}
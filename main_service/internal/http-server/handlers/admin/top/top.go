@@ -0,0 +1,95 @@
+package top
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+type Ranking interface {
+	Top(ctx context.Context, limit int) ([]redis.Z, error)
+}
+
+type Paste struct {
+	Hash  string `json:"hash"`
+	Views int64  `json:"views"`
+}
+
+type Response struct {
+	resp.Response
+	Pastes []Paste `json:"pastes"`
+}
+
+// New godoc
+// @Summary      Самые популярные тексты
+// @Description  Возвращает тексты с наибольшим числом просмотров из рейтинга популярности в redis. В рейтинге могут быть истёкшие и приватные тексты. Только для администраторов.
+// @Tags         admin
+// @Produce      json
+// @Param        limit  query  int  false  "Сколько текстов вернуть (1-100)"  default(20)
+// @Success      200  {object}  object{status=string,pastes=[]object{hash=string,views=int}}  "Рейтинг"
+// @Failure      400  {object}  object{status=string,error=string}  "Некорректный limit"  example({"status": "error", "error": "Invalid limit"})
+// @Failure      401  {object}  object{status=string,error=string}  "API ключ не передан или неверен"  example({"status": "error", "error": "API key is required"})
+// @Failure      403  {object}  object{status=string,error=string}  "Пользователь не администратор"  example({"status": "error", "error": "Forbidden"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Router       /admin/top [get]
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, ranking Ranking, timeout time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.admin.top.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		limit := defaultLimit
+		if raw := r.URL.Query().Get("limit"); raw != "" {
+			v, err := strconv.Atoi(raw)
+			if err != nil || v < 1 || v > maxLimit {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Invalid limit"))
+
+				return
+			}
+			limit = v
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		top, err := ranking.Top(ctx, limit)
+		if err != nil {
+			log.Error("failed to get top pastes", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("Internal error"))
+
+			return
+		}
+
+		pastes := make([]Paste, 0, len(top))
+		for _, z := range top {
+			pastes = append(pastes, Paste{Hash: fmt.Sprint(z.Member), Views: int64(z.Score)})
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Pastes:   pastes,
+		})
+	}
+}
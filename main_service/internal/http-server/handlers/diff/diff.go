@@ -0,0 +1,216 @@
+package diff
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"main_service/internal/lib/api/notfound"
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/lib/textdiff"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+type Response struct {
+	resp.Response
+	HashA   string          `json:"hash_a"`
+	HashB   string          `json:"hash_b"`
+	RevA    int             `json:"rev_a,omitempty"`
+	RevB    int             `json:"rev_b,omitempty"`
+	Context int             `json:"context"`
+	Diff    string          `json:"diff"`
+	Hunks   []textdiff.Hunk `json:"hunks"`
+}
+
+var errTooLarge = errors.New("text is too large to diff")
+
+// New godoc
+// @Summary      Сравнить два текста
+// @Description  Построчно сравнивает два текста (или две ревизии одного текста) и возвращает unified diff и список hunk'ов. Тексты загружаются так же, как в GET /text/{hash}, поэтому популярные тексты берутся из Redis. Размер каждого текста ограничен.
+// @Tags         texts
+// @Produce      json
+// @Param        hashA    path   string  true   "Хеш первого (исходного) текста"
+// @Param        hashB    path   string  true   "Хеш второго текста"
+// @Param        rev_a    query  int     false  "Ревизия первого текста, по умолчанию текущая"
+// @Param        rev_b    query  int     false  "Ревизия второго текста, по умолчанию текущая"
+// @Param        context  query  int     false  "Количество строк контекста вокруг изменений"  default(3)
+// @Success      200  {object}  object{status=string,hash_a=string,hash_b=string,context=int,diff=string,hunks=[]object{old_start=int,old_lines=int,new_start=int,new_lines=int,lines=[]object{op=string,text=string}}}  "Результат сравнения"
+// @Failure      400  {object}  object{status=string,error=string}  "Некорректные параметры"  example({"status": "error", "error": "Invalid context"})
+// @Failure      404  {object}  object{status=string,error=string}  "Текст или ревизия не найдены"  example({"status": "error", "error": "Text not found"})
+// @Failure      410  {object}  object{status=string,error=string,expires_at=string}  "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)"  example({"status": "error", "error": "Text has expired", "expires_at": "2026-10-19T12:00:00Z"})
+// @Failure      451  {object}  object{status=string,error=string,reason=string}  "Текст скрыт или удалён модератором"  example({"status": "error", "error": "Text is unavailable", "reason": "Phishing"})
+// @Failure      413  {object}  object{status=string,error=string}  "Текст слишком большой для сравнения"  example({"status": "error", "error": "Text is too large to diff"})
+// @Failure      415  {object}  object{status=string,error=string}  "Один из текстов бинарный"  example({"status": "error", "error": "Binary content can't be diffed"})
+// @Failure      429  {object}  object{status=string,error=string}  "Превышен лимит запросов, см. Retry-After"  example({"status": "error", "error": "Too many requests"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Failure      504  {object}  object{status=string,error=string}  "Сравнение не уложилось в таймаут"  example({"status": "error", "error": "Request timed out"})
+// @Router       /diff/{hashA}/{hashB} [get]
+// @Security     none
+// @Security     ApiKeyAuth
+func New(
+	log *slog.Logger,
+	textGetter models.TextOperator,
+	timeout time.Duration,
+	maxSize int, // * предельный размер каждого из текстов в байтах
+	defaultContext, maxContext int, // * maxContext — предел параметра context
+	notFound notfound.Policy,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.diff.New"
+
+		start := time.Now()
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hashA, hashB := chi.URLParam(r, "hashA"), chi.URLParam(r, "hashB")
+
+		q := r.URL.Query()
+
+		revA, okA := intParam(q.Get("rev_a"), 0, 1)
+		revB, okB := intParam(q.Get("rev_b"), 0, 1)
+		if !okA || !okB {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid revision"))
+
+			return
+		}
+
+		lines, ok := intParam(q.Get("context"), defaultContext, 0)
+		if !ok || lines > maxContext {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid context"))
+
+			return
+		}
+
+		var viewer models.Viewer
+		if user, ok := auth.UserFromContext(r.Context()); ok {
+			viewer.UserID = user.ID
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		// Размер и тип проверяются по метаданным, чтобы не читать из MinIO то, что всё равно не сравним
+		err := checkInfo(ctx, textGetter, hashA, revA, viewer, maxSize)
+		if err == nil {
+			err = checkInfo(ctx, textGetter, hashB, revB, viewer, maxSize)
+		}
+
+		var textA, textB string
+		if err == nil {
+			textA, err = textGetter.GetText(ctx, hashA, revA, viewer)
+		}
+		if err == nil {
+			textB, err = textGetter.GetText(ctx, hashB, revB, viewer)
+		}
+		// У старых ревизий размера в метаданных может не быть
+		if err == nil && (len(textA) > maxSize || len(textB) > maxSize) {
+			err = errTooLarge
+		}
+
+		var hunks []textdiff.Hunk
+		if err == nil {
+			hunks, err = textdiff.Compute(ctx, textA, textB, lines)
+		}
+		if err != nil {
+			responseError(w, r, log, err, notFound, start)
+
+			return
+		}
+
+		log.Info("Diff computed",
+			slog.String("hash_a", hashA),
+			slog.String("hash_b", hashB),
+			slog.Int("hunks", len(hunks)),
+		)
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			HashA:    hashA,
+			HashB:    hashB,
+			RevA:     revA,
+			RevB:     revB,
+			Context:  lines,
+			Diff:     textdiff.Unified(hunks, "a/"+hashA, "b/"+hashB),
+			Hunks:    hunks,
+		})
+	}
+}
+
+// * checkInfo проверяет по метаданным, что ревизию rev текста hash можно сравнивать
+func checkInfo(ctx context.Context, textGetter models.TextOperator, hash string, rev int, viewer models.Viewer, maxSize int) error {
+	info, err := textGetter.ContentInfo(ctx, hash, rev, viewer)
+	if err != nil {
+		return err
+	}
+
+	if !models.IsText(info.ContentType) {
+		return storage.ErrBinaryContent
+	}
+
+	if info.Size > int64(maxSize) {
+		return errTooLarge
+	}
+
+	return nil
+}
+
+func responseError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error, notFound notfound.Policy, start time.Time) {
+	var takenDown *storage.TakenDownError
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		// Клиент ушёл, отвечать некому
+		return
+	case errors.Is(err, context.DeadlineExceeded):
+		render.Status(r, http.StatusGatewayTimeout)
+		render.JSON(w, r, resp.Error("Request timed out"))
+	case errors.Is(err, errTooLarge):
+		render.Status(r, http.StatusRequestEntityTooLarge)
+		render.JSON(w, r, resp.Error("Text is too large to diff"))
+	case errors.Is(err, storage.ErrBinaryContent):
+		render.Status(r, http.StatusUnsupportedMediaType)
+		render.JSON(w, r, resp.Error("Binary content can't be diffed"))
+	case errors.Is(err, storage.ErrRevNotFound):
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Error("Revision not found"))
+	case errors.As(err, &takenDown):
+		render.Status(r, http.StatusUnavailableForLegalReasons)
+		render.JSON(w, r, resp.TakenDown(takenDown.Reason))
+	case notfound.Match(err):
+		notFound.Respond(w, r, err, start)
+	default:
+		log.Error("failed to get text for diff", sl.Err(err))
+
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("Internal error"))
+	}
+}
+
+// * intParam разбирает необязательный числовой параметр; пустое значение даёт def
+func intParam(raw string, def, min int) (int, bool) {
+	if raw == "" {
+		return def, true
+	}
+
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < min {
+		return 0, false
+	}
+
+	return n, true
+}
@@ -0,0 +1,105 @@
+package diff
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"main_service/internal/lib/api/notfound"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/chi"
+)
+
+const maxSize = 16
+
+// * fakeTexts отдаёт метаданные и содержимое из памяти и считает загрузки содержимого
+type fakeTexts struct {
+	models.TextOperator
+	info  map[string]models.Content
+	texts map[string]string
+	loads int
+}
+
+func (f *fakeTexts) ContentInfo(ctx context.Context, hash string, rev int, viewer models.Viewer) (*models.Content, error) {
+	c, ok := f.info[hash]
+	if !ok {
+		return nil, storage.ErrTextNotFound
+	}
+
+	return &c, nil
+}
+
+func (f *fakeTexts) GetText(ctx context.Context, hash string, rev int, viewer models.Viewer) (string, error) {
+	f.loads++
+
+	return f.texts[hash], nil
+}
+
+func serve(texts *fakeTexts, target string) *httptest.ResponseRecorder {
+	router := chi.NewRouter()
+	router.Get("/diff/{hashA}/{hashB}", New(slog.New(slog.DiscardHandler), texts, time.Second, maxSize, 3, 10, notfound.Policy{}))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+	return rec
+}
+
+func TestDiff(t *testing.T) {
+	text := func(s string) models.Content {
+		return models.Content{ContentType: models.ContentTypeText, Size: int64(len(s))}
+	}
+
+	texts := func() *fakeTexts {
+		return &fakeTexts{
+			info: map[string]models.Content{
+				"a":      text("a\nb\n"),
+				"b":      text("a\nc\n"),
+				"big":    text("0123456789abcdefXYZ"),
+				"bin":    {ContentType: "image/png", Size: 4},
+				"legacy": {ContentType: models.ContentTypeText}, // * размер не сохранён
+			},
+			texts: map[string]string{
+				"a":      "a\nb\n",
+				"b":      "a\nc\n",
+				"legacy": "0123456789abcdefXYZ",
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		code   int
+		loads  int
+	}{
+		{name: "ok", target: "/diff/a/b", code: http.StatusOK, loads: 2},
+		{name: "invalid revision", target: "/diff/a/b?rev_a=0", code: http.StatusBadRequest},
+		{name: "invalid context", target: "/diff/a/b?context=11", code: http.StatusBadRequest},
+		{name: "negative context", target: "/diff/a/b?context=-1", code: http.StatusBadRequest},
+		{name: "too large by metadata", target: "/diff/a/big", code: http.StatusRequestEntityTooLarge},
+		{name: "too large without stored size", target: "/diff/a/legacy", code: http.StatusRequestEntityTooLarge, loads: 2},
+		{name: "binary", target: "/diff/bin/a", code: http.StatusUnsupportedMediaType},
+		{name: "not found", target: "/diff/a/missing", code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := texts()
+			rec := serve(f, tt.target)
+
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.code, rec.Body.String())
+			}
+			// Отклонённое по метаданным содержимое не читается
+			if f.loads != tt.loads {
+				t.Errorf("loaded %d texts, want %d", f.loads, tt.loads)
+			}
+		})
+	}
+}
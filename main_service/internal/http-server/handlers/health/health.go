@@ -0,0 +1,164 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	resp "main_service/internal/lib/api/response"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type Dependency struct {
+	Name   string
+	Pinger Pinger
+}
+
+type DependencyStatus struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+type Response struct {
+	resp.Response
+	Checks map[string]DependencyStatus `json:"checks,omitempty"`
+}
+
+// * Checker проверяет зависимости сервиса и хранит признак остановки
+type Checker struct {
+	deps     []Dependency
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+func NewChecker(timeout time.Duration, deps ...Dependency) *Checker {
+	return &Checker{
+		deps:    deps,
+		timeout: timeout,
+	}
+}
+
+// * Drain переводит сервис в состояние остановки: /readyz начинает отвечать 503,
+// * чтобы балансировщик вывел инстанс из ротации до srv.Shutdown
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// * Check параллельно пингует все зависимости, каждую со своим таймаутом
+func (c *Checker) Check(ctx context.Context) (map[string]DependencyStatus, bool) {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		healthy = true
+		checks  = make(map[string]DependencyStatus, len(c.deps))
+	)
+
+	for _, dep := range c.deps {
+		wg.Add(1)
+
+		go func(dep Dependency) {
+			defer wg.Done()
+
+			pingCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := dep.Pinger.Ping(pingCtx)
+
+			status := DependencyStatus{
+				Status:    StatusUp,
+				LatencyMs: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				status.Status = StatusDown
+				status.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			checks[dep.Name] = status
+			if err != nil {
+				healthy = false
+			}
+		}(dep)
+	}
+
+	wg.Wait()
+
+	return checks, healthy
+}
+
+// Live godoc
+// @Summary      Liveness probe
+// @Description  Сообщает, что процесс запущен. Зависимости не проверяются.
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  object{status=string}  "Процесс жив"  example({"status": "OK"})
+// @Router       /healthz [get]
+// @Security     none
+func Live() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.JSON(w, r, resp.OK())
+	}
+}
+
+// Ready godoc
+// @Summary      Readiness probe
+// @Description  Пингует MySQL, MinIO, Redis и Kafka и возвращает статус каждой зависимости. Во время graceful shutdown всегда отвечает 503.
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  object{status=string,checks=object}  "Все зависимости доступны"  example({"status": "OK", "checks": {"mysql": {"status": "up", "latency_ms": 2}}})
+// @Failure      503  {object}  object{status=string,error=string,checks=object}  "Сервис не готов принимать трафик"  example({"status": "Error", "error": "Dependency is unavailable"})
+// @Router       /readyz [get]
+// @Security     none
+func Ready(log *slog.Logger, checker *Checker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.health.Ready"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if checker.draining.Load() {
+			render.Status(r, http.StatusServiceUnavailable)
+			render.JSON(w, r, Response{Response: resp.Error("Shutting down")})
+
+			return
+		}
+
+		checks, healthy := checker.Check(r.Context())
+		if !healthy {
+			log.Warn("Readiness check failed", slog.Any("checks", checks))
+
+			render.Status(r, http.StatusServiceUnavailable)
+			render.JSON(w, r, Response{
+				Response: resp.Error("Dependency is unavailable"),
+				Checks:   checks,
+			})
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Checks:   checks,
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package pastes
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+type Paste struct {
+	Hash       string    `json:"hash"`
+	Visibility string    `json:"visibility"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type Response struct {
+	resp.Response
+	Pastes []Paste `json:"pastes"`
+	Total  int     `json:"total"`
+	Limit  int     `json:"limit"`
+	Offset int     `json:"offset"`
+}
+
+// New godoc
+// @Summary      Мои тексты
+// @Description  Возвращает тексты владельца API ключа, новые первыми. Истёкшие тексты не попадают в список.
+// @Tags         me
+// @Produce      json
+// @Param        limit   query  int  false  "Размер страницы (1-100)"  default(20)
+// @Param        offset  query  int  false  "Смещение"  default(0)
+// @Success      200  {object}  object{status=string,pastes=[]object{hash=string,visibility=string,created_at=string,expires_at=string},total=int,limit=int,offset=int}  "Страница текстов"
+// @Failure      400  {object}  object{status=string,error=string}  "Некорректные параметры пагинации"  example({"status": "error", "error": "Invalid limit"})
+// @Failure      401  {object}  object{status=string,error=string}  "API ключ не передан или неверен"  example({"status": "error", "error": "API key is required"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Router       /me/pastes [get]
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, lister models.TextOperator, timeout time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.me.pastes.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		user, _ := auth.UserFromContext(r.Context())
+
+		limit, ok := queryInt(r, "limit", defaultLimit)
+		if !ok || limit < 1 || limit > maxLimit {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid limit"))
+
+			return
+		}
+
+		offset, ok := queryInt(r, "offset", 0)
+		if !ok || offset < 0 {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid offset"))
+
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		list, total, err := lister.ListByOwner(ctx, user.ID, limit, offset)
+		if err != nil {
+			log.Error("failed to list pastes", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("Internal error"))
+
+			return
+		}
+
+		pastes := make([]Paste, 0, len(list))
+		for _, p := range list {
+			pastes = append(pastes, Paste{
+				Hash:       p.Hash,
+				Visibility: p.Visibility,
+				CreatedAt:  p.CreatedAt,
+				ExpiresAt:  p.ExpiresAt,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Pastes:   pastes,
+			Total:    total,
+			Limit:    limit,
+			Offset:   offset,
+		})
+	}
+}
+
+func queryInt(r *http.Request, name string, def int) (int, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, true
+	}
+
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, false
+	}
+
+	return v, true
+}
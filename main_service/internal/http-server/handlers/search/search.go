@@ -0,0 +1,160 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultLimit   = 20
+	maxLimit       = 50
+	maxQueryLength = 256
+)
+
+type Result struct {
+	Hash      string    `json:"hash"`
+	Language  string    `json:"language,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Snippet   string    `json:"snippet"`
+	Score     float64   `json:"score"`
+}
+
+type Response struct {
+	resp.Response
+	Results []Result `json:"results"`
+	Limit   int      `json:"limit"`
+	Offset  int      `json:"offset"`
+}
+
+// New godoc
+// @Summary      Поиск по содержимому
+// @Description  Полнотекстовый поиск по неистёкшим текстам. Ищет среди публичных текстов и собственных текстов владельца API ключа. Для каждого результата возвращается фрагмент вокруг найденного слова.
+// @Tags         texts
+// @Produce      json
+// @Param        q       query  string  true   "Поисковый запрос"  maxlength(256)
+// @Param        limit   query  int     false  "Размер страницы (1-50)"  default(20)
+// @Param        offset  query  int     false  "Смещение"  default(0)
+// @Success      200  {object}  object{status=string,results=[]object{hash=string,language=string,created_at=string,snippet=string,score=number},limit=int,offset=int}  "Найденные тексты"
+// @Failure      400  {object}  object{status=string,error=string}  "Некорректные параметры"  example({"status": "error", "error": "Query is required"})
+// @Failure      429  {object}  object{status=string,error=string}  "Превышен лимит запросов, см. Retry-After"  example({"status": "error", "error": "Too many requests"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Failure      504  {object}  object{status=string,error=string}  "Поиск не уложился в таймаут"  example({"status": "error", "error": "Request timed out"})
+// @Router       /search [get]
+// @Security     none
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, searcher models.TextOperator, timeout time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.search.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		q := r.URL.Query().Get("q")
+		if q == "" {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Query is required"))
+
+			return
+		}
+
+		if utf8.RuneCountInString(q) > maxQueryLength {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Query is too long"))
+
+			return
+		}
+
+		limit, ok := queryInt(r, "limit", defaultLimit)
+		if !ok || limit < 1 || limit > maxLimit {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid limit"))
+
+			return
+		}
+
+		offset, ok := queryInt(r, "offset", 0)
+		if !ok || offset < 0 {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid offset"))
+
+			return
+		}
+
+		var viewer models.Viewer
+		if user, ok := auth.UserFromContext(r.Context()); ok {
+			viewer.UserID = user.ID
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		hits, err := searcher.Search(ctx, q, viewer, limit, offset)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				// Клиент ушёл, отвечать некому
+				return
+			}
+
+			if errors.Is(err, context.DeadlineExceeded) {
+				render.Status(r, http.StatusGatewayTimeout)
+				render.JSON(w, r, resp.Error("Request timed out"))
+
+				return
+			}
+
+			log.Error("failed to search", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("Internal error"))
+
+			return
+		}
+
+		results := make([]Result, 0, len(hits))
+		for _, h := range hits {
+			results = append(results, Result{
+				Hash:      h.Hash,
+				Language:  h.Language,
+				CreatedAt: h.CreatedAt,
+				Snippet:   h.Snippet,
+				Score:     h.Score,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Results:  results,
+			Limit:    limit,
+			Offset:   offset,
+		})
+	}
+}
+
+func queryInt(r *http.Request, name string, def int) (int, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, true
+	}
+
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, false
+	}
+
+	return v, true
+}
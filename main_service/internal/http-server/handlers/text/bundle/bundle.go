@@ -0,0 +1,125 @@
+package bundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"main_service/internal/lib/api/notfound"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/bundle"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+// New godoc
+// @Summary      Скачать текст архивом
+// @Description  Возвращает все файлы многофайлового текста одним архивом zip или tar. Обычный текст попадает в архив как единственный файл <hash>.txt.
+// @Tags         texts
+// @Produce      application/zip
+// @Produce      application/x-tar
+// @Param        hash    path   string  true   "Уникальный хеш текста"
+// @Param        format  query  string  false  "Формат архива"  Enums(zip, tar)  default(zip)
+// @Success      200  {file}    file  "Архив"
+// @Failure      400  {object}  object{status=string,error=string}  "Неизвестный формат"  example({"status": "error", "error": "Unknown archive format"})
+// @Failure      404  {object}  object{status=string,error=string}  "Текст не найден"  example({"status": "error", "error": "Text not found"})
+// @Failure      410  {object}  object{status=string,error=string,expires_at=string}  "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)"  example({"status": "error", "error": "Text has expired", "expires_at": "2026-10-19T12:00:00Z"})
+// @Failure      451  {object}  object{status=string,error=string,reason=string}  "Текст скрыт или удалён модератором"  example({"status": "error", "error": "Text is unavailable", "reason": "Phishing"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Router       /text/{hash}/bundle [get]
+// @Security     none
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, textGetter models.TextOperator, timeout time.Duration, notFound notfound.Policy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.text.bundle.New"
+
+		start := time.Now()
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hash := chi.URLParam(r, "hash")
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = bundle.FormatZip
+		}
+
+		if format != bundle.FormatZip && format != bundle.FormatTar {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Unknown archive format"))
+
+			return
+		}
+
+		var viewer models.Viewer
+		if user, ok := auth.UserFromContext(r.Context()); ok {
+			viewer.UserID = user.ID
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		var archive bundle.Writer
+		modTime := time.Now().UTC()
+
+		err := textGetter.ReadFiles(ctx, hash, viewer, func(f models.File, content string) error {
+			// Заголовки отправляем только когда первый файл уже прочитан,
+			// чтобы ошибки доступа успели превратиться в JSON ответ
+			if archive == nil {
+				w.Header().Set("Content-Type", bundle.ContentType(format))
+				w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, hash, format))
+
+				archive, _ = bundle.New(format, w)
+			}
+
+			return archive.Add(f.Name, content, modTime)
+		})
+
+		if archive != nil {
+			if err == nil {
+				err = archive.Close()
+			}
+			if err != nil {
+				// Архив уже частично отправлен, остаётся только оборвать его
+				log.Error("failed to write bundle", sl.Err(err))
+			}
+
+			return
+		}
+
+		var takenDown *storage.TakenDownError
+
+		switch {
+		case err == nil:
+			return
+		case errors.Is(err, context.Canceled):
+			// Клиент ушёл, отвечать некому
+			return
+		case errors.Is(err, context.DeadlineExceeded):
+			render.Status(r, http.StatusGatewayTimeout)
+			render.JSON(w, r, resp.Error("Request timed out"))
+		case errors.As(err, &takenDown):
+			render.Status(r, http.StatusUnavailableForLegalReasons)
+			render.JSON(w, r, resp.TakenDown(takenDown.Reason))
+		case notfound.Match(err):
+			notFound.Respond(w, r, err, start)
+		default:
+			log.Error("failed to read files", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("Internal error"))
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package extend
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"main_service/internal/lib/api/notfound"
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type Request struct {
+	TTL int `json:"ttl" validate:"required,min=1"`
+}
+
+type Response struct {
+	resp.Response
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// New godoc
+// @Summary      Продлить свой текст
+// @Description  Сдвигает время истечения текста на ttl дней. Доступно только владельцу текста.
+// @Tags         texts
+// @Accept       json
+// @Produce      json
+// @Param        hash     path  string                true  "Уникальный хеш текста"
+// @Param        request  body  object{ttl=int}  true  "На сколько дней продлить"  example({"ttl": 7})
+// @Success      200  {object}  object{status=string,expires_at=string}  "Новое время истечения"  example({"status": "OK", "expires_at": "2026-01-01T00:00:00Z"})
+// @Failure      400  {object}  object{status=string,error=string}  "Некорректный запрос"  example({"status": "error", "error": "Field TTL is a required field"})
+// @Failure      401  {object}  object{status=string,error=string}  "API ключ не передан или неверен"  example({"status": "error", "error": "API key is required"})
+// @Failure      403  {object}  object{status=string,error=string}  "Текст принадлежит другому пользователю"  example({"status": "error", "error": "Forbidden"})
+// @Failure      404  {object}  object{status=string,error=string}  "Текст не найден или уже истёк"  example({"status": "error", "error": "Text not found"})
+// @Failure      410  {object}  object{status=string,error=string,expires_at=string}  "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)"  example({"status": "error", "error": "Text has expired", "expires_at": "2026-10-19T12:00:00Z"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Router       /text/{hash}/extend [post]
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, textExtender models.TextOperator, timeout time.Duration, notFound notfound.Policy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.text.extend.New"
+
+		start := time.Now()
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hash := chi.URLParam(r, "hash")
+		user, _ := auth.UserFromContext(r.Context())
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("Failed to decode request body", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		expiresAt, err := textExtender.ExtendOwnText(ctx, hash, user.ID, req.TTL)
+		if err != nil {
+			switch {
+			case notfound.Match(err):
+				notFound.Respond(w, r, err, start)
+			case errors.Is(err, storage.ErrNotOwner):
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.Error("Forbidden"))
+			default:
+				log.Error("failed to extend text", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("Internal error"))
+			}
+
+			return
+		}
+
+		log.Info("Text extended", slog.String("hash", hash), slog.Time("expires_at", expiresAt))
+
+		render.JSON(w, r, Response{
+			Response:  resp.OK(),
+			ExpiresAt: expiresAt,
+		})
+	}
+}
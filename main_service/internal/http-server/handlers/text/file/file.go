@@ -0,0 +1,102 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"main_service/internal/lib/api/notfound"
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+type Response struct {
+	resp.Response
+	Filename string `json:"filename"`
+	Language string `json:"language,omitempty"`
+	Text     string `json:"text"`
+}
+
+// New godoc
+// @Summary      Получить файл многофайлового текста
+// @Description  Возвращает содержимое одного файла многофайлового текста по его имени.
+// @Tags         texts
+// @Produce      json
+// @Param        hash      path  string  true  "Уникальный хеш текста"
+// @Param        filename  path  string  true  "Имя файла"
+// @Success      200  {object}  object{status=string,filename=string,language=string,text=string}  "Файл"  example({"status": "OK", "filename": "app.yaml", "language": "yaml", "text": "port: 8080"})
+// @Failure      404  {object}  object{status=string,error=string}  "Текст или файл не найдены"  example({"status": "error", "error": "File not found"})
+// @Failure      410  {object}  object{status=string,error=string,expires_at=string}  "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)"  example({"status": "error", "error": "Text has expired", "expires_at": "2026-10-19T12:00:00Z"})
+// @Failure      451  {object}  object{status=string,error=string,reason=string}  "Текст скрыт или удалён модератором"  example({"status": "error", "error": "Text is unavailable", "reason": "Phishing"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Failure      504  {object}  object{status=string,error=string}  "Получение не уложилось в таймаут"  example({"status": "error", "error": "Request timed out"})
+// @Router       /text/{hash}/files/{filename} [get]
+// @Security     none
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, textGetter models.TextOperator, timeout time.Duration, notFound notfound.Policy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.text.file.New"
+
+		start := time.Now()
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hash, name := chi.URLParam(r, "hash"), chi.URLParam(r, "filename")
+
+		var viewer models.Viewer
+		if user, ok := auth.UserFromContext(r.Context()); ok {
+			viewer.UserID = user.ID
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		f, text, err := textGetter.FileText(ctx, hash, name, viewer)
+		if err != nil {
+			var takenDown *storage.TakenDownError
+
+			switch {
+			case errors.Is(err, context.Canceled):
+				// Клиент ушёл, отвечать некому
+				return
+			case errors.Is(err, context.DeadlineExceeded):
+				render.Status(r, http.StatusGatewayTimeout)
+				render.JSON(w, r, resp.Error("Request timed out"))
+			case errors.Is(err, storage.ErrFileNotFound):
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("File not found"))
+			case errors.As(err, &takenDown):
+				render.Status(r, http.StatusUnavailableForLegalReasons)
+				render.JSON(w, r, resp.TakenDown(takenDown.Reason))
+			case notfound.Match(err):
+				notFound.Respond(w, r, err, start)
+			default:
+				log.Error("failed to get file", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("Internal error"))
+			}
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Filename: f.Name,
+			Language: f.Language,
+			Text:     text,
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package fork
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"main_service/internal/lib/api/notfound"
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/lib/secrets"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type Request struct {
+	Text       string `json:"text,omitempty"`
+	Rev        int    `json:"rev,omitempty" validate:"omitempty,min=1"`
+	TTL        int    `json:"ttl,omitempty"`
+	Visibility string `json:"visibility,omitempty" validate:"omitempty,oneof=public unlisted private"`
+	OnSecret   string `json:"on_secret,omitempty" validate:"omitempty,oneof=reject redact"`
+}
+
+type Response struct {
+	resp.Response
+	Hash       string `json:"hash"`
+	ParentHash string `json:"parent_hash"`
+}
+
+// New godoc
+// @Summary      Форкнуть текст
+// @Description  Создаёт новый текст из существующего (по умолчанию из текущей ревизии). Если text не передан или совпадает с исходным, форк использует тот же объект в MinIO. Видимость по умолчанию наследуется от исходного текста.
+// @Tags         texts
+// @Accept       json
+// @Produce      json
+// @Param        hash     path  string                                              true   "Хеш исходного текста"
+// @Param        request  body  object{text=string,rev=int,ttl=int,visibility=string,on_secret=string}  false  "Изменённый текст, ревизия исходного текста, TTL и видимость форка; on_secret: reject или redact"  example({"text": "Hello, fork!", "ttl": 7})
+// @Success      201  {object}  object{status=string,hash=string,parent_hash=string}  "Форк создан"  example({"status": "OK", "hash": "f1e2d3", "parent_hash": "a1b2c3"})
+// @Failure      400  {object}  object{status=string,error=string}  "Некорректный запрос"  example({"status": "error", "error": "Private pastes require an API key"})
+// @Failure      401  {object}  object{status=string,error=string}  "Неверный API ключ или анонимный доступ выключен"  example({"status": "error", "error": "Invalid API key"})
+// @Failure      404  {object}  object{status=string,error=string}  "Текст или ревизия не найдены"  example({"status": "error", "error": "Text not found"})
+// @Failure      410  {object}  object{status=string,error=string,expires_at=string}  "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)"  example({"status": "error", "error": "Text has expired", "expires_at": "2026-10-19T12:00:00Z"})
+// @Failure      451  {object}  object{status=string,error=string,reason=string}  "Текст скрыт или удалён модератором"  example({"status": "error", "error": "Text is unavailable", "reason": "Phishing"})
+// @Failure      422  {object}  object{status=string,error=string,secrets=[]object{file=string,line=int,rule=string}}  "В тексте найдены секреты"  example({"status": "error", "error": "Text contains secrets", "secrets": [{"line": 3, "rule": "jwt"}]})
+// @Failure      429  {object}  object{status=string,error=string}  "Превышен лимит запросов, см. Retry-After"  example({"status": "error", "error": "Too many requests"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Failure      504  {object}  object{status=string,error=string}  "Форк не уложился в таймаут"  example({"status": "error", "error": "Request timed out"})
+// @Router       /text/{hash}/fork [post]
+// @Security     none
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, textForker models.TextOperator, defaultTTL int, timeout time.Duration, notFound notfound.Policy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.text.fork.New"
+
+		start := time.Now()
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hash := chi.URLParam(r, "hash")
+
+		var req Request
+
+		// Тело необязательно: без него получается точная копия
+		if r.ContentLength != 0 {
+			if err := render.DecodeJSON(r.Body, &req); err != nil {
+				log.Error("Failed to decode request body", sl.Err(err))
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Failed to decode request"))
+
+				return
+			}
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		timeToLive := req.TTL
+		if timeToLive == 0 {
+			timeToLive = defaultTTL
+		}
+
+		in := models.PasteInput{
+			Text:        req.Text,
+			TTLDays:     timeToLive,
+			Visibility:  req.Visibility,
+			SecretsMode: req.OnSecret,
+		}
+
+		var viewer models.Viewer
+		if user, ok := auth.UserFromContext(r.Context()); ok {
+			in.OwnerID = user.ID
+			viewer.UserID = user.ID
+		}
+
+		if in.Visibility == models.VisibilityPrivate && in.OwnerID == 0 {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Private pastes require an API key"))
+
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		forkHash, err := textForker.ForkText(ctx, hash, req.Rev, viewer, in)
+		if err != nil {
+			var found *secrets.FoundError
+			var takenDown *storage.TakenDownError
+
+			switch {
+			case errors.Is(err, context.Canceled):
+				// Клиент ушёл, отвечать некому
+				return
+			case errors.Is(err, context.DeadlineExceeded):
+				render.Status(r, http.StatusGatewayTimeout)
+				render.JSON(w, r, resp.Error("Request timed out"))
+			case errors.As(err, &found):
+				render.Status(r, http.StatusUnprocessableEntity)
+				render.JSON(w, r, resp.SecretsFound(found.Findings))
+			case errors.Is(err, storage.ErrRevNotFound):
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Revision not found"))
+			case errors.As(err, &takenDown):
+				render.Status(r, http.StatusUnavailableForLegalReasons)
+				render.JSON(w, r, resp.TakenDown(takenDown.Reason))
+			case notfound.Match(err):
+				notFound.Respond(w, r, err, start)
+			default:
+				log.Error("failed to fork text", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("Internal error"))
+			}
+
+			return
+		}
+
+		log.Info("Text forked", slog.String("hash", forkHash), slog.String("parent_hash", hash))
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, Response{
+			Response:   resp.OK(),
+			Hash:       forkHash,
+			ParentHash: hash,
+		})
+	}
+}
@@ -1,13 +1,26 @@
 package get
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
+	"main_service/internal/lib/api/notfound"
 	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/compress"
+	"main_service/internal/lib/highlight"
+	"main_service/internal/lib/httpcache"
 	sl "main_service/internal/lib/logger"
+	"main_service/internal/lib/negotiate"
+	"main_service/internal/lib/signature"
+	"main_service/internal/middleware/auth"
 	"main_service/internal/models"
 	"main_service/internal/storage"
 
@@ -16,30 +29,80 @@ import (
 	"github.com/go-chi/render"
 )
 
+type Verifier interface {
+	Verify(hash string, q url.Values, now time.Time) error
+}
+
 type Response struct {
 	resp.Response
 	Text string `json:"text"`
 }
 
+// * представления текста по заголовку Accept; без него — JSON
+const (
+	mediaJSON   = "application/json"
+	mediaText   = "text/plain"
+	mediaHTML   = "text/html"
+	mediaBinary = "application/octet-stream"
+)
+
+var offers = []string{mediaJSON, mediaText, mediaHTML, mediaBinary}
+
+// * суффиксы ETag представлений; у JSON его нет, ETag совпадает с sha256 содержимого
+var tags = map[string]string{mediaText: "text", mediaHTML: "html", mediaBinary: "bin"}
+
 // New godoc
 // @Summary      Получить текст
-// @Description  Получает сохраненный текст по его уникальному хешу. Популярные тексты кэшируются в Redis для быстрого доступа.
+// @Description  Получает сохраненный текст по его уникальному хешу. Популярные тексты кэшируются в Redis для быстрого доступа. Приватный текст доступен владельцу или по подписанной ссылке (параметры expires, kid, sig).
+// @Description  Формат ответа выбирается по Accept: application/json (по умолчанию) — JSON с текстом, text/plain — сам текст, text/html — страница с подсветкой, application/octet-stream — скачивание файлом. Ответ сжимается br или gzip по Accept-Encoding.
 // @Tags         texts
 // @Accept       json
-// @Produce      json
-// @Param        hash  path  string  true  "Уникальный хеш текста (буквенно-цифровая строка)"  minlength(6)  maxlength(64)  example(a1b2c3d4e5f6)
+// @Produce      json,plain,html,octet-stream
+// @Param        hash     path   string  true   "Уникальный хеш текста (буквенно-цифровая строка)"  minlength(6)  maxlength(64)  example(a1b2c3d4e5f6)
+// @Param        rev      query  int     false  "Номер ревизии, по умолчанию текущая"
+// @Param        expires  query  int     false  "Время истечения подписанной ссылки (unix)"
+// @Param        kid      query  string  false  "Идентификатор ключа подписи"
+// @Param        sig      query  string  false  "HMAC подпись ссылки"
+// @Param        Accept             header  string  false  "application/json, text/plain, text/html или application/octet-stream"
+// @Param        Accept-Encoding    header  string  false  "br, gzip"
+// @Param        If-None-Match      header  string  false  "ETag из предыдущего ответа"
+// @Param        If-Modified-Since  header  string  false  "Last-Modified из предыдущего ответа"
 // @Success      200   {object}  object{status=string,text=string}  "Текст успешно получен"  example({"status": "ok", "text": "Hello, World!"})
-// @Failure      400   {object}  object{status=string,error=string}  "Хеш не указан"  example({"status": "error", "error": "Hash is empty"})
+// @Header       200   {string}  ETag           "sha256 содержимого с суффиксом представления и сжатия"
+// @Header       200   {string}  Last-Modified  "Время создания ревизии"
+// @Header       200   {string}  Cache-Control  "public для неизменяемых публичных текстов, max-age не больше оставшегося срока жизни"
+// @Success      304   "Содержимое не изменилось"
+// @Failure      400   {object}  object{status=string,error=string}  "Хеш не указан или некорректная ревизия"  example({"status": "error", "error": "Hash is empty"})
+// @Failure      403   {object}  object{status=string,error=string}  "Подпись ссылки неверна или истекла"  example({"status": "error", "error": "Invalid or expired signature"})
 // @Failure      404   {object}  object{status=string,error=string}  "Текст не найден"  example({"status": "error", "error": "Text not found"})
+// @Failure      406   {object}  object{status=string,error=string}  "Ни один из форматов Accept не поддерживается"  example({"status": "error", "error": "Not acceptable"})
+// @Failure      410   {object}  object{status=string,error=string,expires_at=string}  "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)"  example({"status": "error", "error": "Text has expired", "expires_at": "2026-10-19T12:00:00Z"})
+// @Failure      451   {object}  object{status=string,error=string,reason=string}  "Текст скрыт или удалён модератором"  example({"status": "error", "error": "Text is unavailable", "reason": "Phishing"})
+// @Failure      413   {object}  object{status=string,error=string}  "Текст слишком большой для подсветки (text/html)"  example({"status": "error", "error": "Text is too large to highlight"})
+// @Failure      415   {object}  object{status=string,error=string}  "Содержимое бинарное, его нужно скачивать через /text/{hash}/raw"  example({"status": "error", "error": "Content is binary, download it from /text/a1b2c3/raw"})
+// @Failure      429   {object}  object{status=string,error=string}  "Превышен лимит запросов или клиент забанен за перебор хэшей, см. Retry-After"  example({"status": "error", "error": "Too many requests"})
 // @Failure      500   {object}  object{status=string,error=string}  "Ошибка при получении текста"  example({"status": "error", "error": "Failed to get text"})
+// @Failure      504   {object}  object{status=string,error=string}  "Получение не уложилось в таймаут"  example({"status": "error", "error": "Request timed out"})
 // @Router       /text/{hash} [get]
 // @Security     none
+// @Security     ApiKeyAuth
 // @x-order      2
-func New(ctx context.Context, log *slog.Logger, textGetter models.TextOperator) http.HandlerFunc {
+func New(
+	log *slog.Logger,
+	textGetter models.TextOperator,
+	verifier Verifier,
+	timeout time.Duration,
+	notFound notfound.Policy, // * ответ на несуществующий и истёкший текст, общий для всех обработчиков
+	cacheMaxAge time.Duration, // * предел max-age неизменяемых текстов, чтобы удалённый модератором текст не жил в CDN до истечения
+	theme string, // * тема и предельный размер страницы text/html, как у /view/{hash}
+	maxHighlight int,
+) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.text.get.New"
 
-		log = log.With(
+		start := time.Now()
+
+		log := log.With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
@@ -54,34 +117,227 @@ func New(ctx context.Context, log *slog.Logger, textGetter models.TextOperator)
 			return
 		}
 
-		text, err := textGetter.GetText(ctx, hash)
+		var rev int
+		if raw := r.URL.Query().Get("rev"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Invalid revision"))
+
+				return
+			}
+			rev = n
+		}
+
+		media, ok := negotiate.Media(r.Header.Get("Accept"), offers)
+		if !ok {
+			render.Status(r, http.StatusNotAcceptable)
+			render.JSON(w, r, resp.Error("Not acceptable"))
+
+			return
+		}
+
+		var viewer models.Viewer
+		if user, ok := auth.UserFromContext(r.Context()); ok {
+			viewer.UserID = user.ID
+		}
+
+		// Подпись проверяется до любых обращений к хранилищам
+		if q := r.URL.Query(); signature.Present(q) {
+			if err := verifier.Verify(hash, q, time.Now()); err != nil {
+				log.Info("Invalid signed link", slog.String("hash", hash), sl.Err(err))
+
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.Error("Invalid or expired signature"))
+
+				return
+			}
+
+			viewer.Signed = true
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		// Условный запрос проверяется по сохранённому sha256, не читая содержимое и не считая просмотр.
+		// Ревизии без sha256 и любые ошибки проверяются обычным путём ниже.
+		if httpcache.Conditional(r) {
+			info, err := textGetter.ContentInfo(ctx, hash, rev, viewer)
+			if err == nil && info.Digest != "" && (media == mediaBinary || models.IsText(info.ContentType)) {
+				etag, cacheControl := validators(r, info, info.Size, media, cacheMaxAge)
+				if httpcache.NotModified(r, etag, info.CreatedAt) {
+					w.Header().Set("Vary", "Accept, Accept-Encoding")
+					httpcache.SetHeaders(w, etag, info.CreatedAt, cacheControl)
+					w.WriteHeader(http.StatusNotModified)
+
+					return
+				}
+			}
+		}
+
+		c, err := textGetter.GetContent(ctx, hash, rev, viewer)
+		// Бинарное содержимое можно только скачать
+		if err == nil && media != mediaBinary && !models.IsText(c.ContentType) {
+			err = storage.ErrBinaryContent
+		}
 		if err != nil {
 			log.Error("failed to get text", sl.Err(err))
 
-			if errors.Is(err, storage.ErrTextNotFound) || errors.Is(err, storage.ErrTTLIsExpired) {
+			if errors.Is(err, context.Canceled) {
+				// Клиент ушёл, отвечать некому
+				return
+			}
+
+			if errors.Is(err, context.DeadlineExceeded) {
+				render.Status(r, http.StatusGatewayTimeout)
+				render.JSON(w, r, resp.Error("Request timed out"))
+
+				return
+			}
+
+			if errors.Is(err, storage.ErrBinaryContent) {
+				render.Status(r, http.StatusUnsupportedMediaType)
+				render.JSON(w, r, resp.Error("Content is binary, download it from /text/"+hash+"/raw"))
+
+				return
+			}
+
+			if errors.Is(err, storage.ErrRevNotFound) {
 				render.Status(r, http.StatusNotFound)
-				render.JSON(w, r, resp.Error("Text not found"))
+				render.JSON(w, r, resp.Error("Revision not found"))
 
 				return
 			}
 
+			var takenDown *storage.TakenDownError
+			if errors.As(err, &takenDown) {
+				render.Status(r, http.StatusUnavailableForLegalReasons)
+				render.JSON(w, r, resp.TakenDown(takenDown.Reason))
+
+				return
+			}
+
+			if notfound.Match(err) {
+				notFound.Respond(w, r, err, start)
+
+				return
+			}
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("Failed to get text"))
+
+			return
+		}
+
+		encoding := encodingFor(r, int64(len(c.Data)))
+		etag, cacheControl := validators(r, c, int64(len(c.Data)), media, cacheMaxAge)
+
+		w.Header().Set("Vary", "Accept, Accept-Encoding")
+
+		if httpcache.NotModified(r, etag, c.CreatedAt) {
+			httpcache.SetHeaders(w, etag, c.CreatedAt, cacheControl)
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		body, err := represent(w, hash, c, media, theme, maxHighlight)
+		if errors.Is(err, highlight.ErrTooLarge) {
+			render.Status(r, http.StatusRequestEntityTooLarge)
+			render.JSON(w, r, resp.Error("Text is too large to highlight"))
+
+			return
+		}
+
+		if err == nil && encoding != "" {
+			encode := func(data string) (string, error) { return compress.Encode(encoding, data) }
+
+			// Тело text/plain и octet-stream — само содержимое, сжатое содержимое текущей ревизии берётся из кэша
+			if rev == 0 && (media == mediaText || media == mediaBinary) {
+				body, err = textGetter.GetEncoded(ctx, hash, c, encoding, encode)
+			} else {
+				body, err = encode(body)
+			}
+		}
+
+		if err != nil {
+			log.Error("failed to render text", sl.Err(err))
+
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, resp.Error("Failed to get text"))
 
 			return
 		}
 
-		w.Header().Set("Cache-Control", "private, max-age=60")
+		log.Info("Text got successfully", slog.String("hash", hash), slog.String("media", media))
 
-		log.Info("Text got successfully", slog.String("hash", hash))
+		httpcache.SetHeaders(w, etag, c.CreatedAt, cacheControl)
+		if encoding != "" {
+			w.Header().Set("Content-Encoding", encoding)
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, body)
+	}
+}
 
-		ResponseOK(w, r, text)
+// * encodingFor выбирает Content-Encoding ответа размером size по Accept-Encoding
+func encodingFor(r *http.Request, size int64) string {
+	if size < compress.MinSize {
+		return ""
 	}
+
+	return negotiate.Encoding(r.Header.Get("Accept-Encoding"), compress.Encodings)
 }
 
-func ResponseOK(w http.ResponseWriter, r *http.Request, text string) {
-	render.JSON(w, r, Response{
-		Response: resp.OK(),
-		Text:     text,
-	})
+// * validators возвращает ETag и Cache-Control представления media содержимого c размером size
+func validators(r *http.Request, c *models.Content, size int64, media string, cacheMaxAge time.Duration) (string, string) {
+	// Разные представления одного содержимого — разные сущности, у каждой свой сильный ETag
+	etag := httpcache.Variant(httpcache.Variant(httpcache.ETag(c), tags[media]), encodingFor(r, size))
+
+	return etag, httpcache.CacheControl(c, time.Now(), cacheMaxAge)
+}
+
+// * represent возвращает тело ответа c в представлении media и выставляет его заголовки
+func represent(w http.ResponseWriter, hash string, c *models.Content, media, theme string, maxHighlight int) (string, error) {
+	h := w.Header()
+	h.Set("X-Content-Type-Options", "nosniff")
+
+	switch media {
+	case mediaText:
+		h.Set("Content-Type", "text/plain; charset=utf-8")
+
+		return c.Data, nil
+	case mediaHTML:
+		page, err := highlight.Page(c.Data, c.Language, highlight.Options{Theme: theme, LineNumbers: true}, maxHighlight)
+		if err != nil {
+			return "", err
+		}
+
+		highlight.SetHeaders(h)
+
+		return page, nil
+	case mediaBinary:
+		ext := ".bin"
+		if models.IsText(c.ContentType) {
+			ext = ".txt"
+		}
+
+		h.Set("Content-Type", mediaBinary)
+		h.Set("Content-Disposition", `attachment; filename="`+hash+ext+`"`)
+
+		return c.Data, nil
+	}
+
+	// Так же, как render.JSON
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(true)
+	if err := enc.Encode(Response{Response: resp.OK(), Text: c.Data}); err != nil {
+		return "", err
+	}
+
+	h.Set("Content-Type", "application/json")
+
+	return buf.String(), nil
 }
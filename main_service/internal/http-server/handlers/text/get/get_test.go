@@ -0,0 +1,128 @@
+package get
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"main_service/internal/lib/api/notfound"
+	"main_service/internal/models"
+
+	"github.com/go-chi/chi"
+)
+
+// * blockingGetter имитирует MySQL и MinIO, которые отвечают только по отмене ctx
+type blockingGetter struct {
+	models.TextOperator
+	called chan struct{}
+}
+
+func (g *blockingGetter) GetContent(ctx context.Context, hash string, rev int, viewer models.Viewer) (*models.Content, error) {
+	close(g.called)
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+type noVerifier struct{}
+
+func (noVerifier) Verify(hash string, q url.Values, now time.Time) error { return nil }
+
+func serve(timeout time.Duration, getter models.TextOperator, req *http.Request) *httptest.ResponseRecorder {
+	router := chi.NewRouter()
+	router.Get("/text/{hash}", New(slog.New(slog.DiscardHandler), getter, noVerifier{}, timeout, notfound.Policy{}, time.Hour, "github", 1<<20))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestGetClientCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	getter := &blockingGetter{called: make(chan struct{})}
+
+	go func() {
+		<-getter.called
+		cancel()
+	}()
+
+	req := httptest.NewRequest(http.MethodGet, "/text/abc", nil).WithContext(ctx)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- serve(time.Minute, getter, req) }()
+
+	select {
+	case rec := <-done:
+		if rec.Body.Len() != 0 || len(rec.Header()) != 0 {
+			t.Errorf("response written to gone client: %d %v %q", rec.Code, rec.Header(), rec.Body.String())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler did not return after the client went away")
+	}
+}
+
+func TestGetTimeout(t *testing.T) {
+	getter := &blockingGetter{called: make(chan struct{})}
+
+	rec := serve(10*time.Millisecond, getter, httptest.NewRequest(http.MethodGet, "/text/abc", nil))
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusGatewayTimeout)
+	}
+}
+
+// * infoGetter знает sha256 текста, а GetContent засчитывать не должен
+type infoGetter struct {
+	models.TextOperator
+	info     models.Content
+	contents int
+}
+
+func (g *infoGetter) ContentInfo(ctx context.Context, hash string, rev int, viewer models.Viewer) (*models.Content, error) {
+	return &g.info, nil
+}
+
+func (g *infoGetter) GetContent(ctx context.Context, hash string, rev int, viewer models.Viewer) (*models.Content, error) {
+	g.contents++
+	c := g.info
+	c.Data = "hello"
+
+	return &c, nil
+}
+
+func TestGetConditionalSkipsContent(t *testing.T) {
+	getter := &infoGetter{info: models.Content{
+		ContentType: models.ContentTypeText,
+		Digest:      models.Digest("hello"),
+		Size:        5,
+		Visibility:  models.VisibilityPublic,
+		CreatedAt:   time.Now().Add(-time.Hour),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}}
+
+	req := httptest.NewRequest(http.MethodGet, "/text/abc", nil)
+	req.Header.Set("Accept", "text/plain")
+	req.Header.Set("If-None-Match", `"`+models.Digest("hello")+`-text"`)
+
+	rec := serve(time.Minute, getter, req)
+
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotModified)
+	}
+	if getter.contents != 0 {
+		t.Errorf("GetContent called %d times for a 304", getter.contents)
+	}
+
+	// Другой ETag — обычный ответ с содержимым
+	req.Header.Set("If-None-Match", `"stale"`)
+	rec = serve(time.Minute, getter, req)
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "hello" {
+		t.Errorf("response = %d %q, want 200 hello", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,151 @@
+package meta
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"main_service/internal/lib/api/notfound"
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+type Fork struct {
+	Hash      string    `json:"hash"`
+	CreatedAt time.Time `json:"created_at"`
+	Forks     []Fork    `json:"forks,omitempty"`
+}
+
+type File struct {
+	Filename string `json:"filename"`
+	Language string `json:"language,omitempty"`
+	Size     int64  `json:"size"`
+}
+
+type Response struct {
+	resp.Response
+	Hash       string    `json:"hash"`
+	Visibility string    `json:"visibility"`
+	CurrentRev int       `json:"current_rev"`
+	ParentHash string    `json:"parent_hash,omitempty"`
+	Language   string    `json:"language,omitempty"`
+	Confidence float64   `json:"language_confidence,omitempty"` // * 1 — язык указан автором
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Files      []File    `json:"files,omitempty"`
+	Forks      []Fork    `json:"forks"`
+}
+
+// New godoc
+// @Summary      Метаданные текста
+// @Description  Возвращает метаданные текста, список файлов (для многофайлового текста), хеш текста, из которого он форкнут, и дерево его форков. В дереве показываются публичные форки и собственные форки пользователя.
+// @Tags         texts
+// @Produce      json
+// @Param        hash  path  string  true  "Уникальный хеш текста"
+// @Success      200  {object}  object{status=string,hash=string,visibility=string,current_rev=int,parent_hash=string,language=string,language_confidence=number,created_at=string,expires_at=string,files=[]object{filename=string,language=string,size=int},forks=[]object{hash=string,created_at=string,forks=[]object}}  "Метаданные текста"
+// @Failure      404  {object}  object{status=string,error=string}  "Текст не найден"  example({"status": "error", "error": "Text not found"})
+// @Failure      410  {object}  object{status=string,error=string,expires_at=string}  "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)"  example({"status": "error", "error": "Text has expired", "expires_at": "2026-10-19T12:00:00Z"})
+// @Failure      451  {object}  object{status=string,error=string,reason=string}  "Текст скрыт или удалён модератором"  example({"status": "error", "error": "Text is unavailable", "reason": "Phishing"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Router       /text/{hash}/meta [get]
+// @Security     none
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, textGetter models.TextOperator, timeout time.Duration, notFound notfound.Policy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.text.meta.New"
+
+		start := time.Now()
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hash := chi.URLParam(r, "hash")
+
+		var viewer models.Viewer
+		if user, ok := auth.UserFromContext(r.Context()); ok {
+			viewer.UserID = user.ID
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		paste, forks, err := textGetter.Metadata(ctx, hash, viewer)
+		var files []models.File
+		if err == nil {
+			files, err = textGetter.Files(ctx, hash, viewer)
+		}
+		if err != nil {
+			var takenDown *storage.TakenDownError
+			if errors.As(err, &takenDown) {
+				render.Status(r, http.StatusUnavailableForLegalReasons)
+				render.JSON(w, r, resp.TakenDown(takenDown.Reason))
+
+				return
+			}
+
+			if notfound.Match(err) {
+				notFound.Respond(w, r, err, start)
+
+				return
+			}
+
+			log.Error("failed to get metadata", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("Internal error"))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:   resp.OK(),
+			Hash:       paste.Hash,
+			Visibility: paste.Visibility,
+			CurrentRev: paste.CurrentRev,
+			ParentHash: paste.ParentHash,
+			Language:   paste.Language,
+			Confidence: paste.LanguageConfidence,
+			CreatedAt:  paste.CreatedAt,
+			ExpiresAt:  paste.ExpiresAt,
+			Files:      toFiles(files),
+			Forks:      toForks(forks),
+		})
+	}
+}
+
+func toFiles(files []models.File) []File {
+	var res []File
+	for _, f := range files {
+		res = append(res, File{
+			Filename: f.Name,
+			Language: f.Language,
+			Size:     f.Size,
+		})
+	}
+
+	return res
+}
+
+func toForks(nodes []models.ForkNode) []Fork {
+	forks := make([]Fork, 0, len(nodes))
+	for _, n := range nodes {
+		forks = append(forks, Fork{
+			Hash:      n.Hash,
+			CreatedAt: n.CreatedAt,
+			Forks:     toForks(n.Forks),
+		})
+	}
+
+	return forks
+}
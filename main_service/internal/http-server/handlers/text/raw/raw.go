@@ -0,0 +1,176 @@
+package raw
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"main_service/internal/lib/api/notfound"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/language"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/lib/signature"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+type Verifier interface {
+	Verify(hash string, q url.Values, now time.Time) error
+}
+
+// * inline — типы, которые браузер может показать сам; остальное отдаётся вложением
+var inline = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// New godoc
+// @Summary      Скачать содержимое
+// @Description  Отдаёт содержимое текста или загруженного файла как есть, с его MIME типом и заголовком X-Content-Type-Options: nosniff. Картинки показываются в браузере, остальные бинарные типы скачиваются вложением.
+// @Tags         texts
+// @Produce      octet-stream
+// @Param        hash     path   string  true   "Уникальный хеш текста"
+// @Param        rev      query  int     false  "Номер ревизии, по умолчанию текущая"
+// @Param        expires  query  int     false  "Время истечения подписанной ссылки (unix)"
+// @Param        kid      query  string  false  "Идентификатор ключа подписи"
+// @Param        sig      query  string  false  "HMAC подпись ссылки"
+// @Success      200  {file}    file  "Содержимое"
+// @Failure      400  {object}  object{status=string,error=string}  "Некорректная ревизия"  example({"status": "error", "error": "Invalid revision"})
+// @Failure      403  {object}  object{status=string,error=string}  "Подпись ссылки неверна или истекла"  example({"status": "error", "error": "Invalid or expired signature"})
+// @Failure      404  {object}  object{status=string,error=string}  "Текст не найден"  example({"status": "error", "error": "Text not found"})
+// @Failure      410  {object}  object{status=string,error=string,expires_at=string}  "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)"  example({"status": "error", "error": "Text has expired", "expires_at": "2026-10-19T12:00:00Z"})
+// @Failure      451  {object}  object{status=string,error=string,reason=string}  "Текст скрыт или удалён модератором"  example({"status": "error", "error": "Text is unavailable", "reason": "Phishing"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Failure      504  {object}  object{status=string,error=string}  "Получение не уложилось в таймаут"  example({"status": "error", "error": "Request timed out"})
+// @Router       /text/{hash}/raw [get]
+// @Security     none
+// @Security     ApiKeyAuth
+func New(
+	log *slog.Logger,
+	textGetter models.TextOperator,
+	verifier Verifier,
+	timeout time.Duration,
+	notFound notfound.Policy, // * ответ на несуществующий и истёкший текст, общий для всех обработчиков
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.text.raw.New"
+
+		start := time.Now()
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hash := chi.URLParam(r, "hash")
+
+		var rev int
+		if raw := r.URL.Query().Get("rev"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Invalid revision"))
+
+				return
+			}
+			rev = n
+		}
+
+		var viewer models.Viewer
+		if user, ok := auth.UserFromContext(r.Context()); ok {
+			viewer.UserID = user.ID
+		}
+
+		// Подпись проверяется до любых обращений к хранилищам
+		if q := r.URL.Query(); signature.Present(q) {
+			if err := verifier.Verify(hash, q, time.Now()); err != nil {
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.Error("Invalid or expired signature"))
+
+				return
+			}
+
+			viewer.Signed = true
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		c, err := textGetter.GetContent(ctx, hash, rev, viewer)
+		if err != nil {
+			var takenDown *storage.TakenDownError
+
+			switch {
+			case errors.Is(err, context.Canceled):
+				// Клиент ушёл, отвечать некому
+				return
+			case errors.Is(err, context.DeadlineExceeded):
+				render.Status(r, http.StatusGatewayTimeout)
+				render.JSON(w, r, resp.Error("Request timed out"))
+			case errors.Is(err, storage.ErrRevNotFound):
+				render.Status(r, http.StatusNotFound)
+				render.JSON(w, r, resp.Error("Revision not found"))
+			case errors.As(err, &takenDown):
+				render.Status(r, http.StatusUnavailableForLegalReasons)
+				render.JSON(w, r, resp.TakenDown(takenDown.Reason))
+			case notfound.Match(err):
+				notFound.Respond(w, r, err, start)
+			default:
+				log.Error("failed to get content", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("Internal error"))
+			}
+
+			return
+		}
+
+		h := w.Header()
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("Content-Security-Policy", "default-src 'none'; sandbox")
+		h.Set("Content-Length", strconv.Itoa(len(c.Data)))
+
+		switch {
+		case models.IsText(c.ContentType):
+			// Любой текст (в том числе html и xml) отдаём как plain или text/x-*, чтобы браузер его не исполнял
+			contentType := language.MimeType(c.Language)
+			if contentType == "" {
+				contentType = "text/plain"
+			}
+			h.Set("Content-Type", contentType+"; charset=utf-8")
+		case inline[c.ContentType]:
+			h.Set("Content-Type", c.ContentType)
+			h.Set("Content-Disposition", "inline")
+		default:
+			h.Set("Content-Type", c.ContentType)
+			h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+				"filename": hash + extension(c.ContentType),
+			}))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(c.Data))
+	}
+}
+
+// * extension подбирает расширение файла по MIME типу
+func extension(contentType string) string {
+	exts, _ := mime.ExtensionsByType(contentType)
+	if len(exts) == 0 {
+		return ".bin"
+	}
+
+	return exts[0]
+}
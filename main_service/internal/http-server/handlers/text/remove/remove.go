@@ -0,0 +1,74 @@
+package remove
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"main_service/internal/lib/api/notfound"
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+// New godoc
+// @Summary      Удалить свой текст
+// @Description  Удаляет текст из MySQL, MinIO и кэша. Доступно только владельцу текста.
+// @Tags         texts
+// @Produce      json
+// @Param        hash  path  string  true  "Уникальный хеш текста"
+// @Success      200  {object}  object{status=string}  "Текст удалён"  example({"status": "OK"})
+// @Failure      401  {object}  object{status=string,error=string}  "API ключ не передан или неверен"  example({"status": "error", "error": "API key is required"})
+// @Failure      403  {object}  object{status=string,error=string}  "Текст принадлежит другому пользователю"  example({"status": "error", "error": "Forbidden"})
+// @Failure      404  {object}  object{status=string,error=string}  "Текст не найден"  example({"status": "error", "error": "Text not found"})
+// @Failure      410  {object}  object{status=string,error=string,expires_at=string}  "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)"  example({"status": "error", "error": "Text has expired", "expires_at": "2026-10-19T12:00:00Z"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Router       /text/{hash} [delete]
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, textDeleter models.TextOperator, timeout time.Duration, notFound notfound.Policy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.text.remove.New"
+
+		start := time.Now()
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hash := chi.URLParam(r, "hash")
+		user, _ := auth.UserFromContext(r.Context())
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		if err := textDeleter.DeleteOwnText(ctx, hash, user.ID); err != nil {
+			switch {
+			case notfound.Match(err):
+				notFound.Respond(w, r, err, start)
+			case errors.Is(err, storage.ErrNotOwner):
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.Error("Forbidden"))
+			default:
+				log.Error("failed to delete text", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("Internal error"))
+			}
+
+			return
+		}
+
+		log.Info("Text deleted", slog.String("hash", hash))
+
+		render.JSON(w, r, resp.OK())
+	}
+}
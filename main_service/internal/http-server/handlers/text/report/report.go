@@ -0,0 +1,122 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"main_service/internal/lib/api/apikey"
+	"main_service/internal/lib/api/notfound"
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type Request struct {
+	Reason  string `json:"reason" validate:"required,oneof=spam malware phishing abuse illegal other"`
+	Comment string `json:"comment,omitempty" validate:"omitempty,max=1000"`
+}
+
+// New godoc
+// @Summary      Пожаловаться на текст
+// @Description  Сохраняет жалобу на текст для модераторов. От одного пользователя (или IP для анонимных читателей) учитывается одна жалоба. После нескольких жалоб от разных читателей текст скрывается до решения модератора.
+// @Tags         texts
+// @Accept       json
+// @Produce      json
+// @Param        hash     path  string                               true  "Уникальный хеш текста"
+// @Param        request  body  object{reason=string,comment=string}  true  "reason: spam, malware, phishing, abuse, illegal или other"  example({"reason": "phishing", "comment": "Fake bank login page"})
+// @Success      202  {object}  object{status=string}  "Жалоба принята"  example({"status": "OK"})
+// @Failure      400  {object}  object{status=string,error=string}  "Некорректный запрос"  example({"status": "error", "error": "Field Reason is a required field"})
+// @Failure      404  {object}  object{status=string,error=string}  "Текст не найден или истёк"  example({"status": "error", "error": "Text not found"})
+// @Failure      410  {object}  object{status=string,error=string,expires_at=string}  "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)"  example({"status": "error", "error": "Text has expired", "expires_at": "2026-10-19T12:00:00Z"})
+// @Failure      429  {object}  object{status=string,error=string}  "Превышен лимит запросов, см. Retry-After"  example({"status": "error", "error": "Too many requests"})
+// @Failure      451  {object}  object{status=string,error=string,reason=string}  "Текст уже скрыт или удалён модератором"  example({"status": "error", "error": "Text is unavailable", "reason": "Phishing"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Router       /text/{hash}/report [post]
+// @Security     none
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, reporter models.Moderator, timeout time.Duration, notFound notfound.Policy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.text.report.New"
+
+		start := time.Now()
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hash := chi.URLParam(r, "hash")
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("Failed to decode request body", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		rep := models.Report{Reason: req.Reason, Comment: req.Comment}
+
+		// Жалобщик хранится только хэшем, IP анонимных читателей в базу не попадает
+		var viewer models.Viewer
+		if user, ok := auth.UserFromContext(r.Context()); ok {
+			viewer.UserID = user.ID
+			rep.ReporterID = user.ID
+		}
+		rep.ReporterKey = apikey.Hash(auth.ClientKey(r))
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		if err := reporter.Report(ctx, hash, viewer, rep); err != nil {
+			var takenDown *storage.TakenDownError
+
+			switch {
+			case errors.Is(err, context.Canceled):
+				// Клиент ушёл, отвечать некому
+			case errors.Is(err, context.DeadlineExceeded):
+				render.Status(r, http.StatusGatewayTimeout)
+				render.JSON(w, r, resp.Error("Request timed out"))
+			case errors.As(err, &takenDown):
+				render.Status(r, http.StatusUnavailableForLegalReasons)
+				render.JSON(w, r, resp.TakenDown(takenDown.Reason))
+			case notfound.Match(err):
+				notFound.Respond(w, r, err, start)
+			default:
+				log.Error("failed to report text", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("Internal error"))
+			}
+
+			return
+		}
+
+		log.Info("Text reported", slog.String("hash", hash), slog.String("reason", req.Reason))
+
+		render.Status(r, http.StatusAccepted)
+		render.JSON(w, r, resp.OK())
+	}
+}
@@ -0,0 +1,108 @@
+package revisions
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"main_service/internal/lib/api/notfound"
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+type Revision struct {
+	Rev       int       `json:"rev"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Response struct {
+	resp.Response
+	Hash      string     `json:"hash"`
+	Revisions []Revision `json:"revisions"`
+}
+
+// New godoc
+// @Summary      История ревизий
+// @Description  Возвращает список ревизий текста по возрастанию номера.
+// @Tags         texts
+// @Produce      json
+// @Param        hash  path  string  true  "Уникальный хеш текста"
+// @Success      200  {object}  object{status=string,hash=string,revisions=[]object{rev=int,size=int,created_at=string}}  "История ревизий"
+// @Failure      404  {object}  object{status=string,error=string}  "Текст не найден"  example({"status": "error", "error": "Text not found"})
+// @Failure      410  {object}  object{status=string,error=string,expires_at=string}  "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)"  example({"status": "error", "error": "Text has expired", "expires_at": "2026-10-19T12:00:00Z"})
+// @Failure      451  {object}  object{status=string,error=string,reason=string}  "Текст скрыт или удалён модератором"  example({"status": "error", "error": "Text is unavailable", "reason": "Phishing"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Router       /text/{hash}/revisions [get]
+// @Security     none
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, textGetter models.TextOperator, timeout time.Duration, notFound notfound.Policy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.text.revisions.New"
+
+		start := time.Now()
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hash := chi.URLParam(r, "hash")
+
+		var viewer models.Viewer
+		if user, ok := auth.UserFromContext(r.Context()); ok {
+			viewer.UserID = user.ID
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		revs, err := textGetter.Revisions(ctx, hash, viewer)
+		if err != nil {
+			var takenDown *storage.TakenDownError
+			if errors.As(err, &takenDown) {
+				render.Status(r, http.StatusUnavailableForLegalReasons)
+				render.JSON(w, r, resp.TakenDown(takenDown.Reason))
+
+				return
+			}
+
+			if notfound.Match(err) {
+				notFound.Respond(w, r, err, start)
+
+				return
+			}
+
+			log.Error("failed to list revisions", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("Internal error"))
+
+			return
+		}
+
+		list := make([]Revision, 0, len(revs))
+		for _, rev := range revs {
+			list = append(list, Revision{
+				Rev:       rev.Rev,
+				Size:      rev.Size,
+				CreatedAt: rev.CreatedAt,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response:  resp.OK(),
+			Hash:      hash,
+			Revisions: list,
+		})
+	}
+}
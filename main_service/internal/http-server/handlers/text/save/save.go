@@ -2,11 +2,16 @@ package save
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 
 	resp "main_service/internal/lib/api/response"
 	sl "main_service/internal/lib/logger"
+	"main_service/internal/lib/secrets"
+	"main_service/internal/middleware/auth"
 	"main_service/internal/models"
 
 	"github.com/go-chi/chi/middleware"
@@ -15,8 +20,20 @@ import (
 )
 
 type Request struct {
-	Text string `json:"text" validate:"required"`
-	TTL  int    `json:"ttl,omitempty"`
+	Text       string `json:"text" validate:"required_without=Files,excluded_with=Files"`
+	Files      []File `json:"files,omitempty" validate:"omitempty,max=50,dive"`
+	Language   string `json:"language,omitempty" validate:"omitempty,max=32"`
+	Filename   string `json:"filename,omitempty" validate:"omitempty,max=255"`
+	TTL        int    `json:"ttl,omitempty"`
+	Visibility string `json:"visibility,omitempty" validate:"omitempty,oneof=public unlisted private"`
+	OnSecret   string `json:"on_secret,omitempty" validate:"omitempty,oneof=reject redact"`
+}
+
+// * File — файл многофайлового текста
+type File struct {
+	Filename string `json:"filename" validate:"required,max=255"`
+	Content  string `json:"content" validate:"required"`
+	Language string `json:"language,omitempty" validate:"omitempty,max=32"`
 }
 
 type Response struct {
@@ -26,22 +43,27 @@ type Response struct {
 
 // New godoc
 // @Summary      Сохранить текст
-// @Description  Сохраняет текст в хранилище и возвращает уникальный хеш для последующего доступа. Текст хранится с указанным TTL (время жизни).
+// @Description  Сохраняет текст в хранилище и возвращает уникальный хеш для последующего доступа. Текст хранится с указанным TTL (время жизни). Если передан API ключ, текст привязывается к его владельцу. Вместо text можно передать files — тогда сохраняется многофайловый текст (до 50 файлов с уникальными именами). Если язык не указан, он определяется по shebang, modeline, расширению filename и содержимому. Текст проверяется на ключи, токены и приватные ключи: такой текст отклоняется (422 с номерами строк) или сохраняется с заменой секретов на [REDACTED:<правило>].
 // @Tags         texts
 // @Accept       json
 // @Produce      json
-// @Param        request  body      object{text=string,ttl=int}  true  "Данные для сохранения"  example({"text": "Hello, World!", "ttl": 3600})
+// @Param        request  body      object{text=string,language=string,filename=string,files=[]object{filename=string,content=string,language=string},ttl=int,visibility=string,on_secret=string}  true  "Данные для сохранения. visibility: public (по умолчанию), unlisted или private (только с API ключом). on_secret: reject или redact — что делать с найденными ключами и токенами (по умолчанию как в конфиге)"  example({"text": "Hello, World!", "ttl": 3600, "visibility": "public"})
 // @Success      201      {object}  object{status=string,hash=string}  "Текст успешно сохранен"  example({"status": "ok", "hash": "a1b2c3d4e5f6"})
 // @Failure      400      {object}  object{status=string,error=string}  "Некорректный запрос"  example({"status": "error", "error": "Text is required"})
+// @Failure      401      {object}  object{status=string,error=string}  "Неверный API ключ или анонимный доступ выключен"  example({"status": "error", "error": "Invalid API key"})
+// @Failure      422      {object}  object{status=string,error=string,secrets=[]object{file=string,line=int,rule=string}}  "В тексте найдены секреты"  example({"status": "error", "error": "Text contains secrets", "secrets": [{"line": 3, "rule": "aws_access_key_id"}]})
+// @Failure      429      {object}  object{status=string,error=string}  "Превышен лимит запросов, см. Retry-After"  example({"status": "error", "error": "Too many requests"})
 // @Failure      500      {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Failed to save text"})
+// @Failure      504      {object}  object{status=string,error=string}  "Сохранение не уложилось в таймаут"  example({"status": "error", "error": "Request timed out"})
 // @Router       /text/save [post]
 // @Security     none
+// @Security     ApiKeyAuth
 // @x-order      1
-func New(ctx context.Context, log *slog.Logger, textSaver models.TextOperator, defaultTTL int) http.HandlerFunc {
+func New(log *slog.Logger, textSaver models.TextOperator, defaultTTL int, timeout time.Duration) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.text.save.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
@@ -70,15 +92,71 @@ func New(ctx context.Context, log *slog.Logger, textSaver models.TextOperator, d
 			return
 		}
 
+		if err := checkFilenames(req.Files); err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(err.Error()))
+
+			return
+		}
+
 		timeToLive := req.TTL
 		if timeToLive == 0 {
 			timeToLive = defaultTTL
 		}
 
-		hash, err := textSaver.SaveText(ctx, req.Text, timeToLive)
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		in := models.PasteInput{
+			Text:        req.Text,
+			Language:    req.Language,
+			Filename:    req.Filename,
+			TTLDays:     timeToLive,
+			Visibility:  req.Visibility,
+			SecretsMode: req.OnSecret,
+		}
+		for _, f := range req.Files {
+			in.Files = append(in.Files, models.FileInput{
+				Name:     f.Filename,
+				Language: f.Language,
+				Content:  f.Content,
+			})
+		}
+		if user, ok := auth.UserFromContext(r.Context()); ok {
+			in.OwnerID = user.ID
+		}
+
+		if in.Visibility == models.VisibilityPrivate && in.OwnerID == 0 {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Private pastes require an API key"))
+
+			return
+		}
+
+		hash, err := textSaver.SaveText(ctx, in)
 		if err != nil {
+			var found *secrets.FoundError
+			if errors.As(err, &found) {
+				render.Status(r, http.StatusUnprocessableEntity)
+				render.JSON(w, r, resp.SecretsFound(found.Findings))
+
+				return
+			}
+
 			log.Error("failed to save text", sl.Err(err))
 
+			if errors.Is(err, context.Canceled) {
+				// Клиент ушёл, отвечать некому
+				return
+			}
+
+			if errors.Is(err, context.DeadlineExceeded) {
+				render.Status(r, http.StatusGatewayTimeout)
+				render.JSON(w, r, resp.Error("Request timed out"))
+
+				return
+			}
+
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, resp.Error("Internal error"))
 
@@ -98,3 +176,20 @@ func ResponseOK(w http.ResponseWriter, r *http.Request, hash string) {
 		Hash:     hash,
 	})
 }
+
+// * checkFilenames проверяет, что имена файлов уникальны и годятся как имена в архиве
+func checkFilenames(files []File) error {
+	seen := make(map[string]struct{}, len(files))
+	for _, f := range files {
+		if strings.ContainsAny(f.Filename, "/\\\x00") || f.Filename == "." || f.Filename == ".." {
+			return errors.New("Invalid filename " + f.Filename)
+		}
+
+		if _, ok := seen[f.Filename]; ok {
+			return errors.New("Duplicate filename " + f.Filename)
+		}
+		seen[f.Filename] = struct{}{}
+	}
+
+	return nil
+}
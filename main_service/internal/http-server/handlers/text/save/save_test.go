@@ -0,0 +1,67 @@
+package save
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"main_service/internal/models"
+)
+
+// * blockingSaver имитирует Kafka и MinIO, которые отвечают только по отмене ctx
+type blockingSaver struct {
+	models.TextOperator
+	called chan struct{}
+}
+
+func (s *blockingSaver) SaveText(ctx context.Context, in models.PasteInput) (string, error) {
+	close(s.called)
+	<-ctx.Done()
+
+	return "", ctx.Err()
+}
+
+func newRequest(ctx context.Context) *http.Request {
+	return httptest.NewRequest(http.MethodPost, "/text/save", strings.NewReader(`{"text":"hello"}`)).WithContext(ctx)
+}
+
+func TestSaveClientCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	saver := &blockingSaver{called: make(chan struct{})}
+
+	go func() {
+		<-saver.called
+		cancel()
+	}()
+
+	rec := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		New(slog.New(slog.DiscardHandler), saver, 1, time.Minute)(rec, newRequest(ctx))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		if rec.Body.Len() != 0 || len(rec.Header()) != 0 {
+			t.Errorf("response written to gone client: %d %v %q", rec.Code, rec.Header(), rec.Body.String())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler did not return after the client went away")
+	}
+}
+
+func TestSaveTimeout(t *testing.T) {
+	saver := &blockingSaver{called: make(chan struct{})}
+
+	rec := httptest.NewRecorder()
+	New(slog.New(slog.DiscardHandler), saver, 1, 10*time.Millisecond)(rec, newRequest(context.Background()))
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusGatewayTimeout)
+	}
+}
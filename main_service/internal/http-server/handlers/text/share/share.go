@@ -0,0 +1,163 @@
+package share
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"main_service/internal/lib/api/notfound"
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/lib/signature"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type Signer interface {
+	Sign(hash string, expires time.Time) (url.Values, error)
+}
+
+type Request struct {
+	// * Время жизни ссылки в секундах
+	ExpiresIn int `json:"expires_in,omitempty" validate:"omitempty,min=1"`
+}
+
+type Response struct {
+	resp.Response
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// New godoc
+// @Summary      Подписанная ссылка на текст
+// @Description  Выдаёт владельцу ссылку с HMAC подписью, по которой текст (в том числе приватный) можно читать без API ключа до истечения срока.
+// @Tags         texts
+// @Accept       json
+// @Produce      json
+// @Param        hash     path  string                  true   "Уникальный хеш текста"
+// @Param        request  body  object{expires_in=int}  false  "Время жизни ссылки в секундах"  example({"expires_in": 3600})
+// @Success      201  {object}  object{status=string,url=string,expires_at=string}  "Ссылка создана"  example({"status": "OK", "url": "https://paste.example.com/text/a1b2c3?expires=1767225600&kid=k1&sig=9f..", "expires_at": "2026-01-01T00:00:00Z"})
+// @Failure      400  {object}  object{status=string,error=string}  "Некорректный срок действия"  example({"status": "error", "error": "Field ExpiresIn is not valid"})
+// @Failure      401  {object}  object{status=string,error=string}  "API ключ не передан или неверен"  example({"status": "error", "error": "API key is required"})
+// @Failure      403  {object}  object{status=string,error=string}  "Текст принадлежит другому пользователю"  example({"status": "error", "error": "Forbidden"})
+// @Failure      404  {object}  object{status=string,error=string}  "Текст не найден"  example({"status": "error", "error": "Text not found"})
+// @Failure      410  {object}  object{status=string,error=string,expires_at=string}  "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)"  example({"status": "error", "error": "Text has expired", "expires_at": "2026-10-19T12:00:00Z"})
+// @Failure      503  {object}  object{status=string,error=string}  "Ключи подписи не настроены"  example({"status": "error", "error": "Signed links are disabled"})
+// @Router       /text/{hash}/share [post]
+// @Security     ApiKeyAuth
+func New(
+	log *slog.Logger,
+	textSharer models.TextOperator,
+	signer Signer,
+	baseURL string,
+	defaultTTL, maxTTL time.Duration,
+	timeout time.Duration,
+	notFound notfound.Policy,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.text.share.New"
+
+		start := time.Now()
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hash := chi.URLParam(r, "hash")
+		user, _ := auth.UserFromContext(r.Context())
+
+		var req Request
+
+		// Тело необязательно: без него ссылка живёт defaultTTL
+		if r.ContentLength != 0 {
+			if err := render.DecodeJSON(r.Body, &req); err != nil {
+				log.Error("Failed to decode request body", sl.Err(err))
+
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Failed to decode request"))
+
+				return
+			}
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		ttl := defaultTTL
+		if req.ExpiresIn > 0 {
+			ttl = time.Duration(req.ExpiresIn) * time.Second
+		}
+		if ttl > maxTTL {
+			ttl = maxTTL
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		paste, err := textSharer.OwnMetadata(ctx, hash, user.ID)
+		if err != nil {
+			switch {
+			case notfound.Match(err):
+				notFound.Respond(w, r, err, start)
+			case errors.Is(err, storage.ErrNotOwner):
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.Error("Forbidden"))
+			default:
+				log.Error("failed to get metadata", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("Internal error"))
+			}
+
+			return
+		}
+
+		// Ссылка не переживает сам текст
+		expiresAt := time.Now().Add(ttl).UTC().Truncate(time.Second)
+		if paste.ExpiresAt.Before(expiresAt) {
+			expiresAt = paste.ExpiresAt.UTC().Truncate(time.Second)
+		}
+
+		q, err := signer.Sign(hash, expiresAt)
+		if err != nil {
+			if errors.Is(err, signature.ErrSigningDisabled) {
+				render.Status(r, http.StatusServiceUnavailable)
+				render.JSON(w, r, resp.Error("Signed links are disabled"))
+
+				return
+			}
+
+			log.Error("failed to sign link", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("Internal error"))
+
+			return
+		}
+
+		log.Info("Signed link issued", slog.String("hash", hash), slog.Time("expires_at", expiresAt))
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, Response{
+			Response:  resp.OK(),
+			URL:       baseURL + "/text/" + url.PathEscape(hash) + "?" + q.Encode(),
+			ExpiresAt: expiresAt,
+		})
+	}
+}
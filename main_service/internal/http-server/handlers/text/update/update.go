@@ -0,0 +1,129 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"main_service/internal/lib/api/notfound"
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/lib/secrets"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type Request struct {
+	Text string `json:"text" validate:"required"`
+}
+
+type Response struct {
+	resp.Response
+	Hash string `json:"hash"`
+	Rev  int    `json:"rev"`
+}
+
+// New godoc
+// @Summary      Изменить свой текст
+// @Description  Сохраняет новую ревизию текста. Предыдущие ревизии остаются доступны через параметр rev. Доступно только владельцу текста. Найденные в тексте секреты обрабатываются так, как задано в конфиге (secrets.mode).
+// @Tags         texts
+// @Accept       json
+// @Produce      json
+// @Param        hash     path  string              true  "Уникальный хеш текста"
+// @Param        request  body  object{text=string}  true  "Новое содержимое"  example({"text": "Hello again!"})
+// @Success      200  {object}  object{status=string,hash=string,rev=int}  "Ревизия создана"  example({"status": "OK", "hash": "a1b2c3", "rev": 2})
+// @Failure      400  {object}  object{status=string,error=string}  "Некорректный запрос"  example({"status": "error", "error": "Field Text is a required field"})
+// @Failure      401  {object}  object{status=string,error=string}  "API ключ не передан или неверен"  example({"status": "error", "error": "API key is required"})
+// @Failure      403  {object}  object{status=string,error=string}  "Текст принадлежит другому пользователю"  example({"status": "error", "error": "Forbidden"})
+// @Failure      404  {object}  object{status=string,error=string}  "Текст не найден или истёк"  example({"status": "error", "error": "Text not found"})
+// @Failure      410  {object}  object{status=string,error=string,expires_at=string}  "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)"  example({"status": "error", "error": "Text has expired", "expires_at": "2026-10-19T12:00:00Z"})
+// @Failure      409  {object}  object{status=string,error=string}  "Многофайловый текст нельзя заменить одним текстом"  example({"status": "error", "error": "Multi-file texts cannot be updated"})
+// @Failure      451  {object}  object{status=string,error=string,reason=string}  "Текст скрыт или удалён модератором"  example({"status": "error", "error": "Text is unavailable", "reason": "Phishing"})
+// @Failure      422  {object}  object{status=string,error=string,secrets=[]object{file=string,line=int,rule=string}}  "В тексте найдены секреты"  example({"status": "error", "error": "Text contains secrets", "secrets": [{"line": 3, "rule": "jwt"}]})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Router       /text/{hash} [put]
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, textUpdater models.TextOperator, timeout time.Duration, notFound notfound.Policy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.text.update.New"
+
+		start := time.Now()
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hash := chi.URLParam(r, "hash")
+		user, _ := auth.UserFromContext(r.Context())
+
+		var req Request
+
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("Failed to decode request body", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.ValidationError(validateErr))
+
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		rev, err := textUpdater.UpdateOwnText(ctx, hash, user.ID, req.Text)
+		if err != nil {
+			var found *secrets.FoundError
+			var takenDown *storage.TakenDownError
+
+			switch {
+			case errors.As(err, &takenDown):
+				render.Status(r, http.StatusUnavailableForLegalReasons)
+				render.JSON(w, r, resp.TakenDown(takenDown.Reason))
+			case notfound.Match(err):
+				notFound.Respond(w, r, err, start)
+			case errors.Is(err, storage.ErrNotOwner):
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.Error("Forbidden"))
+			case errors.Is(err, storage.ErrHasFiles):
+				render.Status(r, http.StatusConflict)
+				render.JSON(w, r, resp.Error("Multi-file texts cannot be updated"))
+			case errors.As(err, &found):
+				render.Status(r, http.StatusUnprocessableEntity)
+				render.JSON(w, r, resp.SecretsFound(found.Findings))
+			default:
+				log.Error("failed to update text", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("Internal error"))
+			}
+
+			return
+		}
+
+		log.Info("Text revision added", slog.String("hash", hash), slog.Int("rev", rev))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Hash:     hash,
+			Rev:      rev,
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/lib/secrets"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+// * сколько multipart формы держим в памяти, остальное уходит во временные файлы
+const maxMemory = 1 << 20
+
+type Response struct {
+	resp.Response
+	Hash        string `json:"hash"`
+	ContentType string `json:"content_type"`
+	Size        int    `json:"size"`
+}
+
+// New godoc
+// @Summary      Загрузить файл
+// @Description  Сохраняет файл (например, скриншот) как текст. Тип определяется по содержимому и должен входить в список разрешённых; у каждого типа свой предел размера. Бинарное содержимое отдаётся только через GET /text/{hash}/raw.
+// @Tags         texts
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file        formData  file    true   "Файл"
+// @Param        ttl         formData  int     false  "Время жизни в днях"
+// @Param        visibility  formData  string  false  "public (по умолчанию), unlisted или private (только с API ключом)"
+// @Success      201  {object}  object{status=string,hash=string,content_type=string,size=int}  "Файл сохранён"  example({"status": "OK", "hash": "a1b2c3", "content_type": "image/png", "size": 48213})
+// @Failure      400  {object}  object{status=string,error=string}  "Некорректный запрос"  example({"status": "error", "error": "File is required"})
+// @Failure      401  {object}  object{status=string,error=string}  "Неверный API ключ или анонимный доступ выключен"  example({"status": "error", "error": "Invalid API key"})
+// @Failure      413  {object}  object{status=string,error=string}  "Файл слишком большой"  example({"status": "error", "error": "File is too large"})
+// @Failure      415  {object}  object{status=string,error=string}  "Тип файла не разрешён"  example({"status": "error", "error": "Unsupported content type application/zip"})
+// @Failure      422  {object}  object{status=string,error=string,secrets=[]object{file=string,line=int,rule=string}}  "В тексте найдены секреты"  example({"status": "error", "error": "Text contains secrets", "secrets": [{"line": 3, "rule": "jwt"}]})
+// @Failure      429  {object}  object{status=string,error=string}  "Превышен лимит запросов, см. Retry-After"  example({"status": "error", "error": "Too many requests"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Failure      504  {object}  object{status=string,error=string}  "Сохранение не уложилось в таймаут"  example({"status": "error", "error": "Request timed out"})
+// @Router       /upload [post]
+// @Security     none
+// @Security     ApiKeyAuth
+func New(
+	log *slog.Logger,
+	textSaver models.TextOperator,
+	types map[string]int64, // * разрешённые MIME типы и предельный размер каждого
+	maxSize int64, // * общий предел файла
+	defaultTTL int,
+	timeout time.Duration,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.text.upload.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		// Запас на заголовки частей и остальные поля формы
+		r.Body = http.MaxBytesReader(w, r.Body, maxSize+maxMemory)
+
+		if err := r.ParseMultipartForm(maxMemory); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				render.Status(r, http.StatusRequestEntityTooLarge)
+				render.JSON(w, r, resp.Error("File is too large"))
+
+				return
+			}
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Failed to decode request"))
+
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("File is required"))
+
+			return
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+		if err != nil {
+			log.Error("failed to read uploaded file", sl.Err(err))
+
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Failed to read file"))
+
+			return
+		}
+
+		if len(data) == 0 {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("File is empty"))
+
+			return
+		}
+
+		// Заявленному клиентом типу не верим, определяем по первым байтам
+		contentType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+
+		limit, ok := types[contentType]
+		if !ok {
+			render.Status(r, http.StatusUnsupportedMediaType)
+			render.JSON(w, r, resp.Error("Unsupported content type "+contentType))
+
+			return
+		}
+
+		if int64(len(data)) > min(limit, maxSize) {
+			render.Status(r, http.StatusRequestEntityTooLarge)
+			render.JSON(w, r, resp.Error("File is too large"))
+
+			return
+		}
+
+		in := models.PasteInput{
+			Text:        string(data),
+			ContentType: contentType,
+			Filename:    header.Filename,
+			TTLDays:     defaultTTL,
+			Visibility:  r.FormValue("visibility"),
+		}
+
+		if raw := r.FormValue("ttl"); raw != "" {
+			ttl, err := strconv.Atoi(raw)
+			if err != nil || ttl < 1 {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Invalid ttl"))
+
+				return
+			}
+			in.TTLDays = ttl
+		}
+
+		switch in.Visibility {
+		case "", models.VisibilityPublic, models.VisibilityUnlisted, models.VisibilityPrivate:
+		default:
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid visibility"))
+
+			return
+		}
+
+		if user, ok := auth.UserFromContext(r.Context()); ok {
+			in.OwnerID = user.ID
+		}
+
+		if in.Visibility == models.VisibilityPrivate && in.OwnerID == 0 {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Private pastes require an API key"))
+
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		hash, err := textSaver.SaveText(ctx, in)
+		if err != nil {
+			var found *secrets.FoundError
+			if errors.As(err, &found) {
+				render.Status(r, http.StatusUnprocessableEntity)
+				render.JSON(w, r, resp.SecretsFound(found.Findings))
+
+				return
+			}
+
+			log.Error("failed to save upload", sl.Err(err))
+
+			if errors.Is(err, context.Canceled) {
+				// Клиент ушёл, отвечать некому
+				return
+			}
+
+			if errors.Is(err, context.DeadlineExceeded) {
+				render.Status(r, http.StatusGatewayTimeout)
+				render.JSON(w, r, resp.Error("Request timed out"))
+
+				return
+			}
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("Internal error"))
+
+			return
+		}
+
+		log.Info("File uploaded", slog.String("hash", hash), slog.String("content_type", contentType))
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, Response{
+			Response:    resp.OK(),
+			Hash:        hash,
+			ContentType: contentType,
+			Size:        len(data),
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package trending
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/models"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 50
+)
+
+var windows = map[string]bool{
+	models.TrendingHour: true,
+	models.TrendingDay:  true,
+	models.TrendingWeek: true,
+}
+
+type Paste struct {
+	Hash      string    `json:"hash"`
+	Language  string    `json:"language,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Views     int64     `json:"views"`
+}
+
+type Response struct {
+	resp.Response
+	Window string  `json:"window"`
+	Pastes []Paste `json:"pastes"`
+}
+
+// New godoc
+// @Summary      Популярное
+// @Description  Возвращает публичные тексты, которые больше всего смотрели за последнее время. window: hour — текущий и предыдущий час, day — последние 24 часа, week — последние 7 суток. Рейтинг пересчитывается раз в минуту.
+// @Tags         texts
+// @Produce      json
+// @Param        window  query  string  false  "Окно: hour, day или week"  default(day)
+// @Param        limit   query  int     false  "Сколько текстов вернуть (1-50)"  default(20)
+// @Success      200  {object}  object{status=string,window=string,pastes=[]object{hash=string,language=string,created_at=string,views=int}}  "Популярные тексты"
+// @Failure      400  {object}  object{status=string,error=string}  "Некорректные параметры"  example({"status": "error", "error": "Invalid window"})
+// @Failure      429  {object}  object{status=string,error=string}  "Превышен лимит запросов, см. Retry-After"  example({"status": "error", "error": "Too many requests"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Router       /trending [get]
+// @Security     none
+// @Security     ApiKeyAuth
+func New(log *slog.Logger, texts models.TextOperator, timeout time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.trending.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		window := r.URL.Query().Get("window")
+		if window == "" {
+			window = models.TrendingDay
+		}
+		if !windows[window] {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Invalid window"))
+
+			return
+		}
+
+		limit := defaultLimit
+		if raw := r.URL.Query().Get("limit"); raw != "" {
+			v, err := strconv.Atoi(raw)
+			if err != nil || v < 1 || v > maxLimit {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Invalid limit"))
+
+				return
+			}
+			limit = v
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		list, err := texts.Trending(ctx, window, limit)
+		if err != nil {
+			log.Error("failed to get trending pastes", sl.Err(err))
+
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error("Internal error"))
+
+			return
+		}
+
+		pastes := make([]Paste, 0, len(list))
+		for _, p := range list {
+			pastes = append(pastes, Paste{
+				Hash:      p.Hash,
+				Language:  p.Language,
+				CreatedAt: p.CreatedAt,
+				Views:     p.Views,
+			})
+		}
+
+		// Рейтинг одинаков для всех и меняется раз в минуту
+		w.Header().Set("Cache-Control", "public, max-age=60")
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Window:   window,
+			Pastes:   pastes,
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package view
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"main_service/internal/lib/api/notfound"
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/lib/highlight"
+	"main_service/internal/lib/language"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/lib/signature"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+type Verifier interface {
+	Verify(hash string, q url.Values, now time.Time) error
+}
+
+// New godoc
+// @Summary      Посмотреть текст с подсветкой
+// @Description  Возвращает HTML страницу с подсвеченным текстом. Язык берётся из параметра lang, из сохранённого при создании или определяется по содержимому. Номера строк — ссылки-якоря вида #L10. HTML популярных текстов кэшируется в Redis.
+// @Tags         texts
+// @Produce      html
+// @Param        hash     path   string  true   "Уникальный хеш текста"
+// @Param        theme    query  string  false  "Тема оформления chroma (github, monokai, dracula, ...)"
+// @Param        lines    query  bool    false  "Показывать номера строк"  default(true)
+// @Param        lang     query  string  false  "Язык подсветки, по умолчанию определяется автоматически"
+// @Param        expires  query  int     false  "Время истечения подписанной ссылки (unix)"
+// @Param        kid      query  string  false  "Идентификатор ключа подписи"
+// @Param        sig      query  string  false  "HMAC подпись ссылки"
+// @Success      200  {string}  string  "HTML страница"
+// @Failure      400  {object}  object{status=string,error=string}  "Неизвестная тема или язык"  example({"status": "error", "error": "Unknown theme"})
+// @Failure      403  {object}  object{status=string,error=string}  "Подпись ссылки неверна или истекла"  example({"status": "error", "error": "Invalid or expired signature"})
+// @Failure      404  {object}  object{status=string,error=string}  "Текст не найден"  example({"status": "error", "error": "Text not found"})
+// @Failure      410  {object}  object{status=string,error=string,expires_at=string}  "Срок жизни текста истёк (при anti_enumeration.uniform_not_found — 404)"  example({"status": "error", "error": "Text has expired", "expires_at": "2026-10-19T12:00:00Z"})
+// @Failure      451  {object}  object{status=string,error=string,reason=string}  "Текст скрыт или удалён модератором"  example({"status": "error", "error": "Text is unavailable", "reason": "Phishing"})
+// @Failure      413  {object}  object{status=string,error=string}  "Текст слишком большой для подсветки"  example({"status": "error", "error": "Text is too large to highlight"})
+// @Failure      415  {object}  object{status=string,error=string}  "Содержимое бинарное"  example({"status": "error", "error": "Binary content can't be highlighted"})
+// @Failure      500  {object}  object{status=string,error=string}  "Внутренняя ошибка сервера"  example({"status": "error", "error": "Internal error"})
+// @Failure      504  {object}  object{status=string,error=string}  "Получение не уложилось в таймаут"  example({"status": "error", "error": "Request timed out"})
+// @Router       /view/{hash} [get]
+// @Security     none
+// @Security     ApiKeyAuth
+func New(
+	log *slog.Logger,
+	textGetter models.TextOperator,
+	verifier Verifier,
+	defaultTheme string,
+	maxSize int, // * предельный размер текста, который ещё подсвечиваем
+	timeout time.Duration,
+	notFound notfound.Policy, // * ответ на несуществующий и истёкший текст, общий для всех обработчиков
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.view.New"
+
+		start := time.Now()
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		hash := chi.URLParam(r, "hash")
+		q := r.URL.Query()
+
+		opts := highlight.Options{
+			Language:    q.Get("lang"),
+			Theme:       q.Get("theme"),
+			LineNumbers: q.Get("lines") != "false" && q.Get("lines") != "0",
+		}
+		if opts.Theme == "" {
+			opts.Theme = defaultTheme
+		}
+
+		if !highlight.ThemeExists(opts.Theme) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("Unknown theme"))
+
+			return
+		}
+
+		// lang входит в ключ кэша, поэтому приводится к каноничному имени: алиасы одного языка
+		// дают одно представление, а произвольные значения не плодят копии страницы в redis
+		if opts.Language != "" {
+			lang, ok := language.Resolve(opts.Language)
+			if !ok {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, resp.Error("Unknown language"))
+
+				return
+			}
+			opts.Language = lang
+		}
+
+		var viewer models.Viewer
+		if user, ok := auth.UserFromContext(r.Context()); ok {
+			viewer.UserID = user.ID
+		}
+
+		// Подпись проверяется до любых обращений к хранилищам
+		if signature.Present(q) {
+			if err := verifier.Verify(hash, q, time.Now()); err != nil {
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, resp.Error("Invalid or expired signature"))
+
+				return
+			}
+
+			viewer.Signed = true
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		// Явный lang из запроса важнее сохранённого языка
+		page, err := textGetter.GetView(ctx, hash, viewer, highlight.Variant(opts), func(c *models.Content) (string, error) {
+			return highlight.Page(c.Data, c.Language, opts, maxSize)
+		})
+		if err != nil {
+			var takenDown *storage.TakenDownError
+
+			switch {
+			case errors.Is(err, context.Canceled):
+				// Клиент ушёл, отвечать некому
+				return
+			case errors.Is(err, context.DeadlineExceeded):
+				render.Status(r, http.StatusGatewayTimeout)
+				render.JSON(w, r, resp.Error("Request timed out"))
+			case errors.Is(err, highlight.ErrTooLarge):
+				render.Status(r, http.StatusRequestEntityTooLarge)
+				render.JSON(w, r, resp.Error("Text is too large to highlight"))
+			case errors.Is(err, storage.ErrBinaryContent):
+				render.Status(r, http.StatusUnsupportedMediaType)
+				render.JSON(w, r, resp.Error("Binary content can't be highlighted"))
+			case errors.As(err, &takenDown):
+				render.Status(r, http.StatusUnavailableForLegalReasons)
+				render.JSON(w, r, resp.TakenDown(takenDown.Reason))
+			case notfound.Match(err):
+				notFound.Respond(w, r, err, start)
+			default:
+				log.Error("failed to render view", sl.Err(err))
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("Internal error"))
+			}
+
+			return
+		}
+
+		highlight.SetHeaders(w.Header())
+
+		render.HTML(w, r, page)
+	}
+}
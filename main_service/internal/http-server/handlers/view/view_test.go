@@ -0,0 +1,93 @@
+package view
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"main_service/internal/lib/api/notfound"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/chi"
+)
+
+// * fakeViews запоминает запрошенные представления и отвечает как на несуществующий текст
+type fakeViews struct {
+	models.TextOperator
+	variants []string
+}
+
+func (f *fakeViews) GetView(ctx context.Context, hash string, viewer models.Viewer, variant string, render func(c *models.Content) (string, error)) (string, error) {
+	f.variants = append(f.variants, variant)
+
+	return "", storage.ErrTextNotFound
+}
+
+type noVerifier struct{}
+
+func (noVerifier) Verify(hash string, q url.Values, now time.Time) error { return nil }
+
+func serve(views *fakeViews, minLatency time.Duration, target string) *httptest.ResponseRecorder {
+	router := chi.NewRouter()
+	router.Get("/view/{hash}", New(slog.New(slog.DiscardHandler), views, noVerifier{}, "github", 1<<20, time.Second, notfound.Policy{Uniform: true, MinLatency: minLatency}))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+	return rec
+}
+
+func TestViewLanguage(t *testing.T) {
+	tests := []struct {
+		lang    string
+		status  int
+		variant string
+	}{
+		{lang: "", status: http.StatusNotFound, variant: "html:github::1"},
+		{lang: "go", status: http.StatusNotFound, variant: "html:github:go:1"},
+		{lang: "golang", status: http.StatusNotFound, variant: "html:github:go:1"},
+		{lang: "PY", status: http.StatusNotFound, variant: "html:github:python:1"},
+		{lang: "no-such-language-1", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			views := &fakeViews{}
+
+			rec := serve(views, 0, "/view/abc?lang="+url.QueryEscape(tt.lang))
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+
+			if tt.variant == "" {
+				if len(views.variants) != 0 {
+					t.Errorf("unknown language reached the cache as %q", views.variants)
+				}
+				return
+			}
+
+			if len(views.variants) != 1 || views.variants[0] != tt.variant {
+				t.Errorf("variants = %q, want %q", views.variants, tt.variant)
+			}
+		})
+	}
+}
+
+func TestViewNotFoundLatency(t *testing.T) {
+	const minLatency = 50 * time.Millisecond
+
+	start := time.Now()
+	rec := serve(&fakeViews{}, minLatency, "/view/abc")
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if elapsed := time.Since(start); elapsed < minLatency {
+		t.Errorf("404 answered in %v, want at least %v", elapsed, minLatency)
+	}
+}
@@ -7,10 +7,18 @@ import (
 	"time"
 
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("main_service/internal/kafka")
+
 type KafkaReader struct {
 	reader *kafka.Reader
+	addr   string
+	topic  string
 }
 
 func New(addr, topic string) *KafkaReader {
@@ -28,23 +36,75 @@ func New(addr, topic string) *KafkaReader {
 				ReadBatchTimeout:  30 * time.Second,
 			},
 		),
+		addr:  addr,
+		topic: topic,
 	}
 }
 
-// * ReadMessage читает хэши из kafka
+// * ReadMessage читает хэши из kafka.
+// * Span чтения является дочерним для текущего запроса и ссылается на span генератора хэшей из заголовков.
 func (r *KafkaReader) ReadMessage(ctx context.Context) (string, error) {
 	const op = "kafka.ReadMessage"
 
+	ctx, span := tracer.Start(ctx, r.topic+" receive",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", r.topic),
+		),
+	)
+	defer span.End()
+
 	msg, err := r.reader.ReadMessage(ctx)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
+	producerCtx := otel.GetTextMapPropagator().Extract(context.Background(), headerCarrier{headers: &msg.Headers})
+	if sc := trace.SpanContextFromContext(producerCtx); sc.IsValid() {
+		span.AddLink(trace.Link{SpanContext: sc})
+	}
+
+	span.SetAttributes(
+		attribute.Int("messaging.kafka.partition", msg.Partition),
+		attribute.Int64("messaging.kafka.offset", msg.Offset),
+	)
+
 	log.Printf("got from kafka: '%s'", string(msg.Value))
 
 	return string(msg.Value), nil
 }
 
+// * Ping проверяет, что брокер доступен и топик существует
+func (r *KafkaReader) Ping(ctx context.Context) error {
+	const op = "kafka.Ping"
+
+	conn, err := kafka.DialContext(ctx, "tcp", r.addr)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	partitions, err := conn.ReadPartitions(r.topic)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(partitions) == 0 {
+		return fmt.Errorf("%s: topic %q has no partitions", op, r.topic)
+	}
+
+	return nil
+}
+
 func (r *KafkaReader) Close() error {
 	return r.reader.Close()
 }
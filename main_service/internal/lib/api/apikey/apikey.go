@@ -0,0 +1,49 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	Header = "X-API-Key"
+
+	keyPrefix   = "pb_"
+	keyBytes    = 24
+	prefixChars = 8
+)
+
+// * FromRequest достаёт API ключ из X-API-Key или из Authorization: Bearer
+func FromRequest(r *http.Request) string {
+	if key := r.Header.Get(Header); key != "" {
+		return strings.TrimSpace(key)
+	}
+
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+
+	return ""
+}
+
+// * Hash возвращает sha256 ключа в hex — именно он хранится в базе
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// * Generate создаёт новый ключ вида pb_<hex> и его короткий префикс для поиска при отзыве
+func Generate() (key, prefix string, err error) {
+	data := make([]byte, keyBytes)
+	if _, err := rand.Read(data); err != nil {
+		return "", "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+
+	key = keyPrefix + hex.EncodeToString(data)
+
+	return key, key[:len(keyPrefix)+prefixChars], nil
+}
@@ -0,0 +1,16 @@
+package client
+
+import (
+	"net"
+	"net/http"
+)
+
+// * IP возвращает IP клиента. За доверенным прокси RemoteAddr заранее подменяет middleware.RealIP.
+func IP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -0,0 +1,63 @@
+package notfound
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	resp "main_service/internal/lib/api/response"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/render"
+)
+
+// * Policy — как все обработчики отвечают на несуществующий и истёкший текст
+type Policy struct {
+	// * Uniform отвечает на истёкший текст 404, как на несуществующий: одинаковым телом
+	// * и не быстрее MinLatency от начала запроса, чтобы по ответу нельзя было отличить одно от другого
+	Uniform    bool
+	MinLatency time.Duration
+}
+
+// * Match сообщает, что err — несуществующий или истёкший текст
+func Match(err error) bool {
+	return errors.Is(err, storage.ErrTextNotFound) || errors.Is(err, storage.ErrTTLIsExpired)
+}
+
+// * Respond отвечает на такую ошибку: 410 с временем истечения или 404. start — начало обработки запроса.
+// * Если клиент ушёл, пока ответ выравнивался по времени, ничего не пишет.
+func (p Policy) Respond(w http.ResponseWriter, r *http.Request, err error, start time.Time) {
+	var expired *storage.ExpiredError
+	if errors.As(err, &expired) && !p.Uniform {
+		render.Status(r, http.StatusGone)
+		render.JSON(w, r, resp.Expired(expired.ExpiresAt))
+
+		return
+	}
+
+	if p.Uniform {
+		if !wait(r, time.Until(start.Add(p.MinLatency))) {
+			return
+		}
+	}
+
+	render.Status(r, http.StatusNotFound)
+	render.JSON(w, r, resp.Error("Text not found"))
+}
+
+// * wait ждёт d или ухода клиента; false — клиент ушёл
+func wait(r *http.Request, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
@@ -0,0 +1,102 @@
+package notfound
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"main_service/internal/storage"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{storage.ErrTextNotFound, true},
+		{storage.ErrTTLIsExpired, true},
+		{&storage.ExpiredError{ExpiresAt: time.Now()}, true},
+		{storage.ErrRevNotFound, false},
+		{context.Canceled, false},
+	}
+
+	for _, tt := range tests {
+		if got := Match(tt.err); got != tt.want {
+			t.Errorf("Match(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRespond(t *testing.T) {
+	expired := &storage.ExpiredError{ExpiresAt: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		err    error
+		code   int
+		body   string
+	}{
+		{name: "expired", err: expired, code: http.StatusGone, body: "2026-10-19T12:00:00Z"},
+		{name: "not found", err: storage.ErrTextNotFound, code: http.StatusNotFound, body: "Text not found"},
+		{name: "uniform expired", policy: Policy{Uniform: true}, err: expired, code: http.StatusNotFound, body: "Text not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.policy.Respond(rec, httptest.NewRequest(http.MethodGet, "/", nil), tt.err, time.Now())
+
+			if rec.Code != tt.code || !strings.Contains(rec.Body.String(), tt.body) {
+				t.Errorf("response = %d %s, want %d with %q", rec.Code, rec.Body.String(), tt.code, tt.body)
+			}
+		})
+	}
+}
+
+func TestRespondUniformLatency(t *testing.T) {
+	const minLatency = 50 * time.Millisecond
+
+	p := Policy{Uniform: true, MinLatency: minLatency}
+	start := time.Now()
+
+	// Истёкший и несуществующий отвечают не быстрее minLatency от начала запроса
+	for _, err := range []error{storage.ErrTextNotFound, &storage.ExpiredError{ExpiresAt: start}} {
+		rec := httptest.NewRecorder()
+		p.Respond(rec, httptest.NewRequest(http.MethodGet, "/", nil), err, start)
+
+		if elapsed := time.Since(start); elapsed < minLatency {
+			t.Errorf("%v answered in %v, want at least %v", err, elapsed, minLatency)
+		}
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%v status = %d, want 404", err, rec.Code)
+		}
+	}
+}
+
+func TestRespondClientGone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	p := Policy{Uniform: true, MinLatency: time.Minute}
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		p.Respond(rec, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx), storage.ErrTextNotFound, time.Now())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Respond kept waiting after the client went away")
+	}
+
+	if rec.Body.Len() != 0 {
+		t.Errorf("response written to gone client: %q", rec.Body.String())
+	}
+}
@@ -3,6 +3,9 @@ package response
 import (
 	"fmt"
 	"strings"
+	"time"
+
+	"main_service/internal/lib/secrets"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -47,3 +50,54 @@ func ValidationError(errs validator.ValidationErrors) Response {
 		Error:  strings.Join(errMsgs, ", "),
 	}
 }
+
+// * Secret — положение найденного секрета без его значения
+type Secret struct {
+	File string `json:"file,omitempty"`
+	Line int    `json:"line"`
+	Rule string `json:"rule"`
+}
+
+type SecretsResponse struct {
+	Response
+	Secrets []Secret `json:"secrets"`
+}
+
+// * SecretsFound — ответ 422 на текст, отклонённый из-за найденных секретов
+func SecretsFound(findings []secrets.Finding) SecretsResponse {
+	list := make([]Secret, 0, len(findings))
+	for _, f := range findings {
+		list = append(list, Secret{File: f.File, Line: f.Line, Rule: f.Rule})
+	}
+
+	return SecretsResponse{
+		Response: Error("Text contains secrets"),
+		Secrets:  list,
+	}
+}
+
+type TakenDownResponse struct {
+	Response
+	Reason string `json:"reason"`
+}
+
+// * TakenDown — ответ 451 на текст, скрытый или удалённый модератором
+func TakenDown(reason string) TakenDownResponse {
+	return TakenDownResponse{
+		Response: Error("Text is unavailable"),
+		Reason:   reason,
+	}
+}
+
+type ExpiredResponse struct {
+	Response
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// * Expired — ответ 410 на текст, срок жизни которого истёк в expiresAt
+func Expired(expiresAt time.Time) ExpiredResponse {
+	return ExpiredResponse{
+		Response:  Error("Text has expired"),
+		ExpiresAt: expiresAt.UTC(),
+	}
+}
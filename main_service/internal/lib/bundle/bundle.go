@@ -0,0 +1,89 @@
+package bundle
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"errors"
+	"io"
+	"time"
+)
+
+const (
+	FormatZip = "zip"
+	FormatTar = "tar"
+)
+
+var ErrUnknownFormat = errors.New("unknown archive format")
+
+// * Writer пишет файлы в архив по одному
+type Writer interface {
+	Add(name, content string, modTime time.Time) error
+	Close() error
+}
+
+// * New возвращает Writer архива format поверх w
+func New(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatZip:
+		return &zipWriter{zw: zip.NewWriter(w)}, nil
+	case FormatTar:
+		return &tarWriter{tw: tar.NewWriter(w)}, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// * ContentType возвращает MIME тип архива format
+func ContentType(format string) string {
+	if format == FormatTar {
+		return "application/x-tar"
+	}
+
+	return "application/zip"
+}
+
+type zipWriter struct {
+	zw *zip.Writer
+}
+
+func (z *zipWriter) Add(name, content string, modTime time.Time) error {
+	f, err := z.zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modTime,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(f, content)
+
+	return err
+}
+
+func (z *zipWriter) Close() error {
+	return z.zw.Close()
+}
+
+type tarWriter struct {
+	tw *tar.Writer
+}
+
+func (t *tarWriter) Add(name, content string, modTime time.Time) error {
+	if err := t.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(content)),
+		ModTime: modTime,
+	}); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(t.tw, content)
+
+	return err
+}
+
+func (t *tarWriter) Close() error {
+	return t.tw.Close()
+}
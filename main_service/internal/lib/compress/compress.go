@@ -0,0 +1,48 @@
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/andybalholm/brotli"
+)
+
+// * поддерживаемые Content-Encoding в порядке предпочтения
+const (
+	Brotli = "br"
+	Gzip   = "gzip"
+)
+
+var Encodings = []string{Brotli, Gzip}
+
+// * MinSize — меньшие ответы не сжимаем: выигрыш меньше накладных расходов
+const MinSize = 1024
+
+// * Encode сжимает data алгоритмом encoding
+func Encode(encoding, data string) (string, error) {
+	var (
+		buf bytes.Buffer
+		w   io.WriteCloser
+	)
+
+	switch encoding {
+	case Brotli:
+		w = brotli.NewWriterLevel(&buf, brotli.DefaultCompression)
+	case Gzip:
+		w = gzip.NewWriter(&buf)
+	default:
+		return "", fmt.Errorf("unknown encoding %q", encoding)
+	}
+
+	if _, err := io.WriteString(w, data); err != nil {
+		return "", err
+	}
+
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
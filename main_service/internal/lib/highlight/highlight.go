@@ -0,0 +1,116 @@
+package highlight
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// * сколько байт от начала текста смотрим при автоопределении языка
+const sampleSize = 16 << 10
+
+var (
+	ErrUnknownTheme = errors.New("unknown theme")
+	ErrTooLarge     = errors.New("text is too large to highlight")
+)
+
+type Options struct {
+	Language    string // * пусто — определить по содержимому
+	Theme       string
+	LineNumbers bool // * номера строк со ссылками-якорями #L<n>
+}
+
+// * ThemeExists сообщает, есть ли тема с таким именем
+func ThemeExists(theme string) bool {
+	_, ok := styles.Registry[theme]
+	return ok
+}
+
+// * Variant возвращает ключ, под которым кэшируется результат Render с такими опциями.
+// * Язык в ключ входит, только если задан явно: иначе берётся сохранённый язык текста.
+func Variant(opts Options) string {
+	lines := 0
+	if opts.LineNumbers {
+		lines = 1
+	}
+
+	return fmt.Sprintf("html:%s:%s:%d", opts.Theme, strings.ToLower(opts.Language), lines)
+}
+
+// * Render подсвечивает text и возвращает готовую HTML страницу
+func Render(text string, opts Options) (string, error) {
+	style, ok := styles.Registry[opts.Theme]
+	if !ok {
+		return "", ErrUnknownTheme
+	}
+
+	lexer := Lexer(text, opts.Language)
+
+	it, err := lexer.Tokenise(nil, text)
+	if err != nil {
+		return "", err
+	}
+
+	formatter := html.New(
+		html.Standalone(true),
+		html.WithLineNumbers(opts.LineNumbers),
+		html.WithLinkableLineNumbers(opts.LineNumbers, "L"),
+		html.TabWidth(4),
+	)
+
+	var sb strings.Builder
+	if err := formatter.Format(&sb, style, it); err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
+
+// * Page подсвечивает текст не больше maxSize байт, больший возвращает ErrTooLarge.
+// * Без языка в opts берётся language, сохранённый у текста.
+func Page(text, language string, opts Options, maxSize int) (string, error) {
+	if len(text) > maxSize {
+		return "", ErrTooLarge
+	}
+
+	if opts.Language == "" {
+		opts.Language = language
+	}
+
+	return Render(text, opts)
+}
+
+// * SetHeaders выставляет заголовки ответа со страницей Page
+func SetHeaders(h http.Header) {
+	h.Set("Content-Type", "text/html; charset=utf-8")
+	h.Set("X-Content-Type-Options", "nosniff")
+	// Страница состоит из разметки и inline стилей chroma, скриптам взяться неоткуда
+	h.Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+}
+
+// * Lexer возвращает лексер языка language, а если он не задан или неизвестен — определённый по началу text
+func Lexer(text, language string) chroma.Lexer {
+	var lexer chroma.Lexer
+	if language != "" {
+		lexer = lexers.Get(language)
+	}
+
+	if lexer == nil {
+		if len(text) > sampleSize {
+			text = text[:sampleSize]
+		}
+		lexer = lexers.Analyse(text)
+	}
+
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+
+	return chroma.Coalesce(lexer)
+}
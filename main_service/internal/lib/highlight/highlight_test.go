@@ -0,0 +1,42 @@
+package highlight
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPage(t *testing.T) {
+	const text = "func main() {}\n"
+
+	if _, err := Page(text, "go", Options{Theme: "github"}, len(text)-1); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("Page() error = %v, want ErrTooLarge", err)
+	}
+
+	tests := []struct {
+		name     string
+		language string
+		opts     Options
+		want     string
+	}{
+		{name: "stored language", language: "go", opts: Options{Theme: "github"}, want: "go"},
+		{name: "explicit language wins", language: "go", opts: Options{Theme: "github", Language: "python"}, want: "python"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Page(text, tt.language, tt.opts, len(text))
+			if err != nil {
+				t.Fatalf("Page() error = %v", err)
+			}
+
+			want, err := Render(text, Options{Theme: "github", Language: tt.want})
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			if got != want {
+				t.Errorf("Page() did not highlight as %s", tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package httpcache
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"main_service/internal/models"
+)
+
+// * ETag возвращает сильный ETag содержимого c по его sha256
+func ETag(c *models.Content) string {
+	digest := c.Digest
+	if digest == "" {
+		digest = models.Digest(c.Data)
+	}
+
+	return `"` + digest + `"`
+}
+
+// * Variant добавляет к сильному ETag суффикс представления, например "<digest>-gzip". Пустой suffix не меняет ETag.
+func Variant(etag, suffix string) string {
+	if suffix == "" {
+		return etag
+	}
+
+	return strings.TrimSuffix(etag, `"`) + "-" + suffix + `"`
+}
+
+// * CacheControl возвращает Cache-Control для содержимого c.
+// * Неизменяемые тексты кэшируются до истечения, но не дольше maxAge; публичные — в том числе CDN.
+// * Текущую ревизию текста с владельцем тот может заменить, поэтому её нужно перепроверять каждый раз.
+// * Приватные тексты в общие кэши не попадают.
+func CacheControl(c *models.Content, now time.Time, maxAge time.Duration) string {
+	scope := "private"
+	if c.Visibility == models.VisibilityPublic {
+		scope = "public"
+	}
+
+	if c.Visibility == models.VisibilityPrivate || c.Mutable {
+		return scope + ", no-cache"
+	}
+
+	age := c.ExpiresAt.Sub(now)
+	if maxAge > 0 && age > maxAge {
+		age = maxAge
+	}
+	if age < 0 {
+		age = 0
+	}
+
+	if scope == "public" {
+		return fmt.Sprintf("public, max-age=%d, immutable", int(age/time.Second))
+	}
+
+	return fmt.Sprintf("private, max-age=%d", int(age/time.Second))
+}
+
+// * SetHeaders выставляет ETag, Last-Modified и Cache-Control ответа
+func SetHeaders(w http.ResponseWriter, etag string, lastModified time.Time, cacheControl string) {
+	h := w.Header()
+	h.Set("ETag", etag)
+	if !lastModified.IsZero() {
+		h.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	h.Set("Cache-Control", cacheControl)
+}
+
+// * Conditional сообщает, что r — условный запрос, на который может подойти 304
+func Conditional(r *http.Request) bool {
+	return (r.Method == http.MethodGet || r.Method == http.MethodHead) &&
+		(r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "")
+}
+
+// * NotModified сообщает, что у клиента уже есть эта версия и можно ответить 304.
+// * If-None-Match проверяется первым, If-Modified-Since — только без него (RFC 9110, 13.2.2).
+func NotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+
+		// Last-Modified передаётся с точностью до секунды
+		return !lastModified.Truncate(time.Second).After(t)
+	}
+
+	return false
+}
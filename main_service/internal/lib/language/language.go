@@ -0,0 +1,215 @@
+package language
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+)
+
+// * определение смотрит только на начало текста, чтобы не тормозить на больших вставках
+const sampleSize = 8 << 10
+
+// * уверенность каждого из способов определения
+const (
+	ConfidenceExplicit  = 1.0 // * язык указал клиент
+	ConfidenceShebang   = 0.95
+	ConfidenceModeline  = 0.9
+	ConfidenceExtension = 0.85
+	ConfidenceHeuristic = 0.6
+	ConfidenceAnalyser  = 0.4
+)
+
+// * interpreters — интерпретаторы из shebang, чьё имя не совпадает с алиасом лексера
+var interpreters = map[string]string{
+	"sh":   "bash",
+	"zsh":  "bash",
+	"ksh":  "bash",
+	"dash": "bash",
+	"node": "javascript",
+	"deno": "typescript",
+}
+
+var (
+	versionSuffix = regexp.MustCompile(`[0-9.]+$`)
+
+	// vim: set ft=python : / vi: filetype=go / ex: syntax=sh
+	vimModeline = regexp.MustCompile(`(?:vim?|ex):.*?\b(?:ft|filetype|syntax)=([\w+#-]+)`)
+	// -*- mode: python -*- / -*- python -*-
+	emacsModeline = regexp.MustCompile(`-\*-\s*(?:.*?mode:\s*)?([\w+#-]+)\s*(?:;.*?)?-\*-`)
+)
+
+// * heuristic — признак языка в содержимом
+type heuristic struct {
+	language string
+	re       *regexp.Regexp
+}
+
+// * порядок важен: более специфичные признаки идут раньше
+var heuristics = []heuristic{
+	{"php", regexp.MustCompile(`^\s*<\?php`)},
+	{"html", regexp.MustCompile(`(?i)^\s*(?:<!doctype html|<html[\s>])`)},
+	{"xml", regexp.MustCompile(`^\s*<\?xml\s`)},
+	{"go", regexp.MustCompile(`(?m)^package \w+\s*$[\s\S]*^(?:import|func|type|var|const)\b`)},
+	{"rust", regexp.MustCompile(`(?m)^\s*(?:pub\s+)?fn \w+[^\n]*\)\s*(?:->[^\n]*)?\{|^use \w+::`)},
+	{"c", regexp.MustCompile(`(?m)^#include\s*[<"]`)},
+	{"java", regexp.MustCompile(`(?m)^\s*(?:public\s+)?(?:final\s+)?class \w+[^\n]*\{[\s\S]*\b(?:public|private|protected)\s+(?:static\s+)?\w+`)},
+	{"python", regexp.MustCompile(`(?m)^(?:def \w+\(.*\)\s*(?:->.*)?:|class \w+(?:\(.*\))?:|from [\w.]+ import |import \w+$)`)},
+	{"javascript", regexp.MustCompile(`(?m)^\s*(?:const|let|var) \w+\s*=\s*require\(|^\s*(?:export\s+)?(?:async\s+)?function\s*\w*\s*\(|=>\s*\{|console\.log\(`)},
+	{"sql", regexp.MustCompile(`(?im)^\s*(?:select\s[\s\S]+\sfrom\s|insert\s+into\s|create\s+(?:table|index|view)\s|alter\s+table\s|update\s+\w+\s+set\s)`)},
+	{"json", regexp.MustCompile(`^\s*(?:\{\s*"[^"]*"\s*:|\[\s*(?:\{|"|\d|true|false|null))`)},
+	{"makefile", regexp.MustCompile(`(?m)^[\w.-]+:[^=\n]*\n\t\S`)},
+	{"yaml", regexp.MustCompile(`(?m)\A(?:---\s*\n)?(?:#[^\n]*\n)*[\w.-]+:(?:\s[^\n]*)?\n(?:(?:\s+|- |#)[^\n]*\n|[\w.-]+:(?:\s[^\n]*)?\n)+`)},
+	{"ini", regexp.MustCompile(`(?m)\A(?:[;#][^\n]*\n|\s*\n)*\[[\w. -]+\]\s*\n\s*[\w.-]+\s*=`)},
+	{"diff", regexp.MustCompile(`(?m)^(?:diff --git |--- \S+[^\n]*\n\+\+\+ \S+)`)},
+	{"docker", regexp.MustCompile(`(?m)\A(?:#[^\n]*\n|\s*\n)*FROM\s+\S+`)},
+	{"markdown", regexp.MustCompile(`(?m)^#{1,6} \S[\s\S]*(?:^\s*[-*] \S|\[[^\]]+\]\([^)]+\)|^` + "```" + `)`)},
+}
+
+// * Detect определяет язык текста: по shebang, modeline, расширению filename, эвристикам
+// * и анализаторам chroma, в таком порядке. Возвращает имя языка (см. Name) и уверенность от 0 до 1.
+// * Пустой язык означает обычный текст.
+func Detect(filename, text string) (string, float64) {
+	sample := text
+	if len(sample) > sampleSize {
+		sample = sample[:sampleSize]
+	}
+
+	if lang := shebang(sample); lang != "" {
+		return lang, ConfidenceShebang
+	}
+
+	if lang := modeline(sample); lang != "" {
+		return lang, ConfidenceModeline
+	}
+
+	if filename != "" {
+		if lexer := lexers.Match(path.Base(filename)); lexer != nil {
+			return Name(lexer), ConfidenceExtension
+		}
+	}
+
+	for _, h := range heuristics {
+		if h.re.MatchString(sample) {
+			return Normalize(h.language), ConfidenceHeuristic
+		}
+	}
+
+	if lexer := lexers.Analyse(sample); lexer != nil {
+		return Name(lexer), ConfidenceAnalyser
+	}
+
+	return "", 0
+}
+
+// * Normalize приводит название или алиас языка от клиента к каноничному имени (см. Name).
+// * Неизвестный язык возвращается как есть в нижнем регистре.
+func Normalize(lang string) string {
+	if lexer := lexers.Get(lang); lexer != nil {
+		return Name(lexer)
+	}
+
+	return strings.ToLower(lang)
+}
+
+// * Resolve возвращает каноничное имя (см. Name) известного языка или алиаса lang.
+// * false — такого языка нет.
+func Resolve(lang string) (string, bool) {
+	lexer := lexers.Get(lang)
+	if lexer == nil {
+		return "", false
+	}
+
+	return Name(lexer), true
+}
+
+// * Name возвращает каноничное имя языка лексера — его название в нижнем регистре
+func Name(lexer chroma.Lexer) string {
+	return strings.ToLower(lexer.Config().Name)
+}
+
+// * MimeType возвращает MIME тип текста на языке lang, если он текстовый (text/*), иначе пустую строку
+func MimeType(lang string) string {
+	if lang == "" {
+		return ""
+	}
+
+	lexer := lexers.Get(lang)
+	if lexer == nil {
+		return ""
+	}
+
+	for _, mt := range lexer.Config().MimeTypes {
+		if strings.HasPrefix(mt, "text/x-") {
+			return mt
+		}
+	}
+
+	return ""
+}
+
+// * shebang разбирает первую строку вида #!/usr/bin/env python3 или #!/bin/bash
+func shebang(sample string) string {
+	if !strings.HasPrefix(sample, "#!") {
+		return ""
+	}
+
+	line, _, _ := strings.Cut(sample[2:], "\n")
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	interpreter := path.Base(fields[0])
+	if interpreter == "env" {
+		// #!/usr/bin/env -S python3 -u
+		interpreter = ""
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") {
+				interpreter = path.Base(f)
+				break
+			}
+		}
+	}
+
+	interpreter = versionSuffix.ReplaceAllString(interpreter, "")
+	if interpreter == "" {
+		return ""
+	}
+
+	if lang, ok := interpreters[interpreter]; ok {
+		return Normalize(lang)
+	}
+
+	if lexer := lexers.Get(interpreter); lexer != nil {
+		return Name(lexer)
+	}
+
+	return ""
+}
+
+// * modeline ищет modeline vim или emacs в первых и последних строках образца
+func modeline(sample string) string {
+	lines := strings.SplitN(sample, "\n", 6)
+	if len(lines) > 5 {
+		lines = lines[:5]
+	}
+	if i := strings.LastIndex(strings.TrimRight(sample, "\n"), "\n"); i >= 0 && len(sample) < sampleSize {
+		// vim смотрит и в конец файла, но только если он попал в образец целиком
+		lines = append(lines, sample[i+1:])
+	}
+
+	for _, line := range lines {
+		for _, re := range []*regexp.Regexp{vimModeline, emacsModeline} {
+			if m := re.FindStringSubmatch(line); m != nil {
+				if lexer := lexers.Get(m[1]); lexer != nil {
+					return Name(lexer)
+				}
+			}
+		}
+	}
+
+	return ""
+}
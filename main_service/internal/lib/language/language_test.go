@@ -0,0 +1,96 @@
+package language
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name       string
+		filename   string
+		text       string
+		lang       string
+		confidence float64
+	}{
+		{name: "empty", text: "", lang: "", confidence: 0},
+		{name: "plain text", text: "just some words\n", lang: "", confidence: 0},
+
+		{name: "shebang env", text: "#!/usr/bin/env python3\nprint(1)\n", lang: "python", confidence: ConfidenceShebang},
+		{name: "shebang env flags", text: "#!/usr/bin/env -S deno run\nx\n", lang: "typescript", confidence: ConfidenceShebang},
+		{name: "shebang alias", text: "#!/bin/sh\necho hi\n", lang: "bash", confidence: ConfidenceShebang},
+		{name: "shebang interpreter", text: "#!/usr/bin/node\nx\n", lang: "javascript", confidence: ConfidenceShebang},
+		{name: "shebang version", text: "#!/usr/bin/perl5.30\nx\n", lang: "perl", confidence: ConfidenceShebang},
+		{name: "shebang beats extension", filename: "x.yaml", text: "#!/bin/bash\necho\n", lang: "bash", confidence: ConfidenceShebang},
+
+		{name: "vim modeline", text: "# vim: set ft=python :\nx = 1\n", lang: "python", confidence: ConfidenceModeline},
+		{name: "emacs modeline", text: "# -*- mode: ruby -*-\nputs 1\n", lang: "ruby", confidence: ConfidenceModeline},
+		{name: "emacs short modeline", text: "/* -*- c++ -*- */\nint x;\n", lang: "c++", confidence: ConfidenceModeline},
+		{name: "modeline at the end", text: "x = 1\ny = 2\nz = 3\na = 4\nb = 5\nc = 6\n# vim: ft=python\n", lang: "python", confidence: ConfidenceModeline},
+
+		{name: "extension yaml", filename: "config.yaml", text: "a: 1\n", lang: "yaml", confidence: ConfidenceExtension},
+		{name: "extension go", filename: "main.go", text: "x := 1\n", lang: "go", confidence: ConfidenceExtension},
+		{name: "file name", filename: "Dockerfile", text: "RUN x\n", lang: "docker", confidence: ConfidenceExtension},
+		{name: "file name in directory", filename: "dir/Makefile", text: "all:\n\techo\n", lang: "makefile", confidence: ConfidenceExtension},
+		{name: "unknown extension", filename: "notes.unknownext", text: "hello world\n", lang: "", confidence: 0},
+
+		{name: "heuristic php", text: "<?php echo 1;", lang: "php", confidence: ConfidenceHeuristic},
+		{name: "heuristic html", text: "<!DOCTYPE html>\n<html></html>", lang: "html", confidence: ConfidenceHeuristic},
+		{name: "heuristic go", text: "package main\n\nimport \"fmt\"\n", lang: "go", confidence: ConfidenceHeuristic},
+		{name: "heuristic rust", text: "fn main() {\n}\n", lang: "rust", confidence: ConfidenceHeuristic},
+		{name: "heuristic c", text: "#include <stdio.h>\n", lang: "c", confidence: ConfidenceHeuristic},
+		{name: "heuristic python", text: "def f(x):\n    return x\n", lang: "python", confidence: ConfidenceHeuristic},
+		{name: "heuristic javascript", text: "const x = require('y')\n", lang: "javascript", confidence: ConfidenceHeuristic},
+		{name: "heuristic sql", text: "SELECT a FROM t;\n", lang: "sql", confidence: ConfidenceHeuristic},
+		{name: "heuristic json", text: "{\"a\": 1}", lang: "json", confidence: ConfidenceHeuristic},
+		{name: "heuristic yaml", text: "name: x\nversion: 1\n", lang: "yaml", confidence: ConfidenceHeuristic},
+		{name: "heuristic ini", text: "[section]\nkey = v\n", lang: "ini", confidence: ConfidenceHeuristic},
+		{name: "heuristic diff", text: "diff --git a/x b/x\n", lang: "diff", confidence: ConfidenceHeuristic},
+		{name: "heuristic docker", text: "FROM alpine\nRUN x\n", lang: "docker", confidence: ConfidenceHeuristic},
+		{name: "heuristic markdown", text: "# Title\n\n- item\n", lang: "markdown", confidence: ConfidenceHeuristic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lang, confidence := Detect(tt.filename, tt.text)
+			if lang != tt.lang || confidence != tt.confidence {
+				t.Errorf("Detect(%q, %q) = %q, %v, want %q, %v", tt.filename, tt.text, lang, confidence, tt.lang, tt.confidence)
+			}
+		})
+	}
+}
+
+func TestDetectLooksOnlyAtSample(t *testing.T) {
+	// Признак за пределами образца не учитывается
+	text := strings.Repeat("just some words\n", sampleSize/16+1) + "<?php echo 1;"
+
+	if lang, _ := Detect("", text); lang != "" {
+		t.Errorf("Detect() = %q, want plain text", lang)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		lang string
+		want string
+		ok   bool
+	}{
+		{"go", "go", true},
+		{"golang", "go", true},
+		{"Python", "python", true},
+		{"sh", "bash", true},
+		{"no-such-language", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := Resolve(tt.lang)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Resolve(%q) = %q, %v, want %q, %v", tt.lang, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	// Неизвестный язык Normalize возвращает как есть
+	if got := Normalize("No-Such-Language"); got != "no-such-language" {
+		t.Errorf("Normalize() = %q", got)
+	}
+}
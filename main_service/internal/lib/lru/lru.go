@@ -0,0 +1,94 @@
+package lru
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// * Cache — потокобезопасный LRU кэш с ограничением по суммарному размеру значений.
+// * У каждой записи своё время истечения; истёкшие записи удаляются при обращении к ним.
+type Cache[V any] struct {
+	mu       sync.Mutex
+	maxBytes int64
+	bytes    int64
+	ll       *list.List // * от недавно использованных к давно
+	items    map[string]*list.Element
+}
+
+type entry[V any] struct {
+	key       string
+	value     V
+	size      int64
+	expiresAt time.Time
+}
+
+// * New создаёт кэш, который держит записи суммарным размером не больше maxBytes
+func New[V any](maxBytes int64) *Cache[V] {
+	return &Cache[V]{
+		maxBytes: maxBytes,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// * Get возвращает неистёкшее значение key и поднимает его в начало очереди
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+
+	el, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+
+	e := el.Value.(*entry[V])
+	if !time.Now().Before(e.expiresAt) {
+		c.remove(el)
+		return zero, false
+	}
+
+	c.ll.MoveToFront(el)
+
+	return e.value, true
+}
+
+// * Add кладёт value размером size под ключом key до expiresAt, вытесняя давно не использованные записи.
+// * Значение больше всего кэша не сохраняется.
+func (c *Cache[V]) Add(key string, value V, size int64, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+
+	if size > c.maxBytes || !time.Now().Before(expiresAt) {
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&entry[V]{key: key, value: value, size: size, expiresAt: expiresAt})
+	c.bytes += size
+
+	for c.bytes > c.maxBytes {
+		c.remove(c.ll.Back())
+	}
+}
+
+// * Remove удаляет key из кэша
+func (c *Cache[V]) Remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+}
+
+func (c *Cache[V]) remove(el *list.Element) {
+	e := c.ll.Remove(el).(*entry[V])
+	delete(c.items, e.key)
+	c.bytes -= e.size
+}
@@ -0,0 +1,135 @@
+package lru
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+	c := New[string](3)
+	expires := time.Now().Add(time.Hour)
+
+	c.Add("a", "a", 1, expires)
+	c.Add("b", "b", 1, expires)
+	c.Add("c", "c", 1, expires)
+
+	// a становится самой свежей, давней остаётся b
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a is missing")
+	}
+
+	c.Add("d", "d", 1, expires)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("b survived eviction")
+	}
+	for _, key := range []string{"a", "c", "d"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("%s was evicted", key)
+		}
+	}
+}
+
+func TestCapacity(t *testing.T) {
+	c := New[string](10)
+	expires := time.Now().Add(time.Hour)
+
+	c.Add("a", "a", 4, expires)
+	c.Add("b", "b", 4, expires)
+
+	// 4 + 4 + 6 > 10: вытесняется только давняя a
+	c.Add("c", "c", 6, expires)
+	if _, ok := c.Get("a"); ok {
+		t.Error("a survived eviction")
+	}
+	if c.bytes != 10 || c.ll.Len() != 2 {
+		t.Errorf("cache holds %d bytes in %d entries, want 10 in 2", c.bytes, c.ll.Len())
+	}
+
+	// Значение больше всего кэша не сохраняется и ничего не вытесняет
+	c.Add("huge", "huge", 11, expires)
+	if _, ok := c.Get("huge"); ok {
+		t.Error("value larger than the cache was stored")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Error("c was evicted by a value that does not fit")
+	}
+
+	// Перезапись ключа не учитывает старый размер дважды
+	c.Add("c", "c", 2, expires)
+	if c.bytes != 6 {
+		t.Errorf("cache holds %d bytes after overwrite, want 6", c.bytes)
+	}
+
+	c.Remove("c")
+	c.Remove("b")
+	if c.bytes != 0 || len(c.items) != 0 || c.ll.Len() != 0 {
+		t.Errorf("cache holds %d bytes in %d entries after Remove, want empty", c.bytes, len(c.items))
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	c := New[string](10)
+
+	c.Add("stale", "stale", 1, time.Now().Add(-time.Second))
+	if _, ok := c.Get("stale"); ok {
+		t.Error("already expired value was stored")
+	}
+
+	c.Add("short", "short", 1, time.Now().Add(20*time.Millisecond))
+	if _, ok := c.Get("short"); !ok {
+		t.Fatal("short is missing before expiry")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if _, ok := c.Get("short"); ok {
+		t.Error("short is returned after expiry")
+	}
+	// Истёкшая запись удаляется при обращении и не занимает место
+	if c.bytes != 0 || len(c.items) != 0 {
+		t.Errorf("expired entry still holds %d bytes", c.bytes)
+	}
+}
+
+// * запускать с -race
+func TestConcurrentAccess(t *testing.T) {
+	const (
+		workers = 8
+		ops     = 1000
+		keys    = 32
+	)
+
+	c := New[int](keys / 2)
+	expires := time.Now().Add(time.Hour)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < ops; i++ {
+				key := strconv.Itoa((w*ops + i) % keys)
+
+				switch i % 3 {
+				case 0:
+					c.Add(key, i, 1, expires)
+				case 1:
+					if v, ok := c.Get(key); ok && v%3 != 0 {
+						t.Errorf("Get(%s) = %d, a value that was never added", key, v)
+					}
+				case 2:
+					c.Remove(key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if c.bytes > c.maxBytes || int(c.bytes) != len(c.items) || c.ll.Len() != len(c.items) {
+		t.Errorf("inconsistent cache: %d bytes, %d items, %d list entries", c.bytes, len(c.items), c.ll.Len())
+	}
+}
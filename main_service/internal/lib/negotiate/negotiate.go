@@ -0,0 +1,111 @@
+package negotiate
+
+import (
+	"strconv"
+	"strings"
+)
+
+// * Media выбирает из offers тип для заголовка Accept (RFC 9110, 12.5.1).
+// * Пустой Accept принимает любой тип, тогда выбирается первый из offers.
+// * При равном q выигрывает более точный диапазон, затем порядок offers. false — ничего не подходит.
+func Media(accept string, offers []string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0], true
+	}
+
+	ranges := parse(accept)
+
+	best, bestQ, bestSpecificity := "", 0.0, -1
+	for _, offer := range offers {
+		q, specificity := 0.0, -1
+		for _, r := range ranges {
+			s := mediaMatch(r.value, offer)
+			if s > specificity {
+				q, specificity = r.q, s
+			}
+		}
+
+		if specificity < 0 || q <= 0 {
+			continue
+		}
+
+		if q > bestQ || (q == bestQ && specificity > bestSpecificity) {
+			best, bestQ, bestSpecificity = offer, q, specificity
+		}
+	}
+
+	return best, bestQ > 0
+}
+
+// * mediaMatch возвращает точность совпадения диапазона с типом: 2 — точно, 1 — type/*, 0 — */*, -1 — нет
+func mediaMatch(mediaRange, offer string) int {
+	switch {
+	case mediaRange == offer:
+		return 2
+	case mediaRange == "*/*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(offer, strings.TrimSuffix(mediaRange, "*")):
+		return 1
+	}
+
+	return -1
+}
+
+// * Encoding выбирает из offers кодирование для заголовка Accept-Encoding (RFC 9110, 12.5.3).
+// * Возвращает пустую строку, если подходит только identity.
+func Encoding(acceptEncoding string, offers []string) string {
+	ranges := parse(acceptEncoding)
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		q, found := 0.0, false
+		for _, r := range ranges {
+			if r.value == offer {
+				q, found = r.q, true
+				break
+			}
+			if r.value == "*" && !found {
+				q = r.q
+			}
+		}
+
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best
+}
+
+type weighted struct {
+	value string
+	q     float64
+}
+
+// * parse разбирает список вида "a;q=0.5, b" в значения в нижнем регистре с весами
+func parse(header string) []weighted {
+	var res []weighted
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+
+		value := strings.ToLower(strings.TrimSpace(params[0]))
+		if value == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, p := range params[1:] {
+			k, v, ok := strings.Cut(strings.TrimSpace(p), "=")
+			if !ok || strings.ToLower(strings.TrimSpace(k)) != "q" {
+				continue
+			}
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				q = f
+			}
+		}
+
+		res = append(res, weighted{value: value, q: q})
+	}
+
+	return res
+}
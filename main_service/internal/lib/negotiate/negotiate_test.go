@@ -0,0 +1,63 @@
+package negotiate
+
+import "testing"
+
+var offers = []string{"application/json", "text/plain", "text/html", "application/octet-stream"}
+
+func TestMedia(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+		ok     bool
+	}{
+		{name: "empty", accept: "", want: "application/json", ok: true},
+		{name: "any", accept: "*/*", want: "application/json", ok: true},
+		{name: "exact", accept: "text/html", want: "text/html", ok: true},
+		{name: "type wildcard", accept: "text/*", want: "text/plain", ok: true},
+		{name: "type wildcard wins tie over any", accept: "text/*, */*", want: "text/plain", ok: true},
+		{name: "exact wins tie over type wildcard", accept: "text/*, text/html", want: "text/html", ok: true},
+		{name: "exact wins tie over any", accept: "*/*, application/octet-stream", want: "application/octet-stream", ok: true},
+		{name: "higher q wins over specificity", accept: "text/*;q=0.5, */*", want: "application/json", ok: true},
+		{name: "q", accept: "application/json;q=0.1, text/plain;q=0.9", want: "text/plain", ok: true},
+		{name: "case insensitive", accept: "TEXT/HTML", want: "text/html", ok: true},
+		{name: "zero q excludes", accept: "text/plain;q=0", want: "", ok: false},
+		{name: "specific zero q overrides wildcard", accept: "text/*, text/plain;q=0", want: "text/html", ok: true},
+		{name: "nothing matches", accept: "image/png", want: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Media(tt.accept, offers)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Media(%q) = %q, %v; want %q, %v", tt.accept, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	encodings := []string{"br", "gzip"}
+
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{name: "empty", accept: "", want: ""},
+		{name: "identity only", accept: "identity", want: ""},
+		{name: "gzip", accept: "gzip, deflate", want: "gzip"},
+		{name: "offer order on tie", accept: "gzip, br", want: "br"},
+		{name: "q", accept: "br;q=0.5, gzip", want: "gzip"},
+		{name: "wildcard", accept: "*", want: "br"},
+		{name: "explicit zero overrides wildcard", accept: "*, br;q=0", want: "gzip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Encoding(tt.accept, encodings); got != tt.want {
+				t.Errorf("Encoding(%q) = %q; want %q", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package secrets
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// * что делать с текстом, в котором найдены секреты
+const (
+	ModeOff    = "off"    // * не проверять
+	ModeReject = "reject" // * отклонить текст
+	ModeRedact = "redact" // * заменить секреты на маркер и сохранить
+)
+
+// * Rule — правило поиска секрета: регулярное выражение и, при необходимости, проверка энтропии.
+// * Энтропия отсекает заглушки вроде password = "changeme" там, где выражение слишком общее.
+type Rule struct {
+	Name       string
+	Pattern    *regexp.Regexp
+	Group      int     // * какая группа выражения — сам секрет; 0 — всё совпадение
+	MinEntropy float64 // * минимальная энтропия секрета в битах на символ; 0 — не проверять
+}
+
+// * Finding — найденный секрет. Значение секрета не хранится, только его положение.
+type Finding struct {
+	Rule  string
+	File  string // * имя файла многофайлового текста, пусто для обычного текста
+	Line  int    // * номер строки, с которой начинается секрет, с 1
+	Start int    // * смещение начала секрета в байтах
+	End   int
+}
+
+// * FoundError возвращается, когда текст отклонён из-за найденных секретов
+type FoundError struct {
+	Findings []Finding
+}
+
+func (e *FoundError) Error() string {
+	lines := make([]string, 0, len(e.Findings))
+	for _, f := range e.Findings {
+		lines = append(lines, strconv.Itoa(f.Line))
+	}
+
+	return "secrets found on lines " + strings.Join(lines, ", ")
+}
+
+// * NewRule компилирует правило из конфига
+func NewRule(name, pattern string, group int, minEntropy float64) (Rule, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Rule{}, fmt.Errorf("rule %s: %w", name, err)
+	}
+
+	if group < 0 || group > re.NumSubexp() {
+		return Rule{}, fmt.Errorf("rule %s: no group %d in pattern", name, group)
+	}
+
+	return Rule{Name: name, Pattern: re, Group: group, MinEntropy: minEntropy}, nil
+}
+
+// * DefaultRules — встроенные правила для самых частых утечек
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			Name:    "aws_access_key_id",
+			Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA)[0-9A-Z]{16}\b`),
+		},
+		{
+			Name:       "aws_secret_access_key",
+			Pattern:    regexp.MustCompile(`(?i)aws[_.-]?secret[_.-]?(?:access[_.-]?)?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})\b`),
+			Group:      1,
+			MinEntropy: 3.5,
+		},
+		{
+			Name:    "private_key",
+			Pattern: regexp.MustCompile(`(?s)-----BEGIN [A-Z0-9 ]*PRIVATE KEY(?: BLOCK)?-----(?:.*?-----END [A-Z0-9 ]*PRIVATE KEY(?: BLOCK)?-----)?`),
+		},
+		{
+			Name:    "jwt",
+			Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`),
+		},
+		{
+			Name:    "github_token",
+			Pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{60,})\b`),
+		},
+		{
+			Name:    "slack_token",
+			Pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`),
+		},
+		{
+			Name:    "stripe_key",
+			Pattern: regexp.MustCompile(`\b[sr]k_live_[A-Za-z0-9]{20,}\b`),
+		},
+		{
+			Name:       "generic_secret",
+			Pattern:    regexp.MustCompile(`(?i)(?:secret|token|passw(?:or)?d|api[_-]?key)["']?\s*[:=]\s*["']?([A-Za-z0-9_+/=.-]{16,})`),
+			Group:      1,
+			MinEntropy: 3.5,
+		},
+	}
+}
+
+type Scanner struct {
+	rules []Rule
+}
+
+func New(rules []Rule) *Scanner {
+	return &Scanner{rules: rules}
+}
+
+// * Scan возвращает секреты в text по порядку. Пересекающиеся совпадения разных правил
+// * схлопываются в одно — первое по порядку правил.
+func (s *Scanner) Scan(text string) []Finding {
+	var found []Finding
+
+	for _, rule := range s.rules {
+		for _, m := range rule.Pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := m[2*rule.Group], m[2*rule.Group+1]
+			if start < 0 {
+				continue
+			}
+
+			if rule.MinEntropy > 0 && Entropy(text[start:end]) < rule.MinEntropy {
+				continue
+			}
+
+			found = append(found, Finding{Rule: rule.Name, Start: start, End: end})
+		}
+	}
+
+	if len(found) == 0 {
+		return nil
+	}
+
+	// Стабильная сортировка сохраняет порядок правил для совпадений с одинаковым началом
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Start < found[j].Start })
+
+	merged := found[:1]
+	for _, f := range found[1:] {
+		last := &merged[len(merged)-1]
+		if f.Start < last.End {
+			last.End = max(last.End, f.End)
+			continue
+		}
+		merged = append(merged, f)
+	}
+
+	line, pos := 1, 0
+	for i := range merged {
+		line += strings.Count(text[pos:merged[i].Start], "\n")
+		pos = merged[i].Start
+		merged[i].Line = line
+	}
+
+	return merged
+}
+
+// * Redact заменяет найденные секреты маркером с именем правила.
+// * findings должны быть результатом Scan для того же text.
+func Redact(text string, findings []Finding) string {
+	var b strings.Builder
+	b.Grow(len(text))
+
+	pos := 0
+	for _, f := range findings {
+		b.WriteString(text[pos:f.Start])
+		b.WriteString("[REDACTED:" + f.Rule + "]")
+		pos = f.End
+	}
+	b.WriteString(text[pos:])
+
+	return b.String()
+}
+
+// * Entropy — энтропия Шеннона строки в битах на символ
+func Entropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	n := 0
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		h -= p * math.Log2(p)
+	}
+
+	return h
+}
@@ -0,0 +1,126 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	ParamExpires = "expires"
+	ParamKeyID   = "kid"
+	ParamSig     = "sig"
+)
+
+var (
+	ErrSigningDisabled  = errors.New("signing keys are not configured")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrExpired          = errors.New("signature is expired")
+)
+
+// * placeholder — ключ-заглушка из старых примеров конфига. Он общеизвестен, подписанные им ссылки может подделать любой.
+const placeholder = "change-me-to-a-random-secret-of-at-least-32-bytes"
+
+// * Signer подписывает ссылки на тексты HMAC-SHA256.
+// * Новые ссылки подписываются активным ключом, проверка принимает любой ключ из keys —
+// * так старые ссылки продолжают работать после ротации, пока ключ не убран из конфига.
+type Signer struct {
+	activeKID string
+	keys      map[string][]byte
+}
+
+// * New отказывается принимать ключи короче 32 байт и ключ-заглушку из примеров.
+// * Без ключей подпись выключена: Sign возвращает ErrSigningDisabled.
+func New(activeKID string, keys map[string]string) (*Signer, error) {
+	const op = "signature.New"
+
+	s := &Signer{
+		activeKID: activeKID,
+		keys:      make(map[string][]byte, len(keys)),
+	}
+
+	for kid, secret := range keys {
+		if len(secret) < 32 {
+			return nil, fmt.Errorf("%s: key %q is shorter than 32 bytes", op, kid)
+		}
+		if secret == placeholder {
+			return nil, fmt.Errorf("%s: key %q is the example placeholder, generate a random one", op, kid)
+		}
+		s.keys[kid] = []byte(secret)
+	}
+
+	if activeKID != "" {
+		if _, ok := s.keys[activeKID]; !ok {
+			return nil, fmt.Errorf("%s: active key %q is not in keys", op, activeKID)
+		}
+	}
+
+	return s, nil
+}
+
+// * Sign возвращает query параметры подписанной ссылки на hash, действующей до expires
+func (s *Signer) Sign(hash string, expires time.Time) (url.Values, error) {
+	key, ok := s.keys[s.activeKID]
+	if s.activeKID == "" || !ok {
+		return nil, ErrSigningDisabled
+	}
+
+	exp := strconv.FormatInt(expires.Unix(), 10)
+
+	q := url.Values{}
+	q.Set(ParamExpires, exp)
+	q.Set(ParamKeyID, s.activeKID)
+	q.Set(ParamSig, sign(key, hash, exp))
+
+	return q, nil
+}
+
+// * Present сообщает, есть ли в запросе подпись
+func Present(q url.Values) bool {
+	return q.Get(ParamSig) != ""
+}
+
+// * Verify проверяет подпись ссылки на hash
+func (s *Signer) Verify(hash string, q url.Values, now time.Time) error {
+	key, ok := s.keys[q.Get(ParamKeyID)]
+	if !ok {
+		return ErrInvalidSignature
+	}
+
+	exp := q.Get(ParamExpires)
+
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	got, err := hex.DecodeString(q.Get(ParamSig))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	want, _ := hex.DecodeString(sign(key, hash, exp))
+	if !hmac.Equal(got, want) {
+		return ErrInvalidSignature
+	}
+
+	if now.Unix() >= expUnix {
+		return ErrExpired
+	}
+
+	return nil
+}
+
+func sign(key []byte, hash, expires string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(hash))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(expires))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
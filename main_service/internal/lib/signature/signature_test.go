@@ -0,0 +1,77 @@
+package signature
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var secret = strings.Repeat("0123456789abcdef", 3)
+
+func TestNewRejectsWeakKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{name: "short", key: "too-short-secret"},
+		{name: "31 bytes", key: secret[:31]},
+		{name: "placeholder", key: placeholder},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New("k1", map[string]string{"k1": tt.key}); err == nil {
+				t.Error("New() accepted a weak key")
+			}
+		})
+	}
+
+	if _, err := New("k1", map[string]string{"k1": secret[:32]}); err != nil {
+		t.Errorf("New() with a 32 byte key error = %v", err)
+	}
+}
+
+func TestSignDisabledWithoutKeys(t *testing.T) {
+	s, err := New("", nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := s.Sign("abc", time.Now().Add(time.Hour)); !errors.Is(err, ErrSigningDisabled) {
+		t.Errorf("Sign() error = %v, want ErrSigningDisabled", err)
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	now := time.Now()
+
+	s, err := New("k1", map[string]string{"k1": secret})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	q, err := s.Sign("abc", now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	if err := s.Verify("abc", q, now); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if err := s.Verify("abd", q, now); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() for another hash error = %v, want ErrInvalidSignature", err)
+	}
+	if err := s.Verify("abc", q, now.Add(2*time.Hour)); !errors.Is(err, ErrExpired) {
+		t.Errorf("Verify() after expiry error = %v, want ErrExpired", err)
+	}
+
+	// После ротации старые ссылки работают, пока старый ключ в конфиге
+	rotated, err := New("k2", map[string]string{"k1": secret, "k2": strings.ToUpper(secret)})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := rotated.Verify("abc", q, now); err != nil {
+		t.Errorf("Verify() after rotation error = %v", err)
+	}
+}
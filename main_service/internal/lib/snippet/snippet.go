@@ -0,0 +1,68 @@
+package snippet
+
+import (
+	"strings"
+	"unicode"
+)
+
+const ellipsis = "…"
+
+// * Make вырезает из body фрагмент длиной около width символов вокруг первого вхождения
+// * любого слова из query (без учёта регистра). Если слов нет в тексте, возвращает его начало.
+func Make(body, query string, width int) string {
+	runes := []rune(body)
+	if len(runes) <= width {
+		return body
+	}
+
+	pos := -1
+	for _, term := range strings.FieldsFunc(query, isSeparator) {
+		if i := index(runes, []rune(term)); i >= 0 && (pos < 0 || i < pos) {
+			pos = i
+		}
+	}
+
+	start := 0
+	if pos > width/2 {
+		start = pos - width/2
+	}
+
+	end := min(start+width, len(runes))
+	if end-start < width {
+		start = max(0, end-width)
+	}
+
+	res := string(runes[start:end])
+	if start > 0 {
+		res = ellipsis + res
+	}
+	if end < len(runes) {
+		res += ellipsis
+	}
+
+	return res
+}
+
+// * isSeparator отделяет слова запроса так же грубо, как полнотекстовый индекс MySQL
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+}
+
+// * index ищет term в s без учёта регистра и возвращает позицию в рунах
+func index(s, term []rune) int {
+	if len(term) == 0 {
+		return -1
+	}
+
+outer:
+	for i := 0; i+len(term) <= len(s); i++ {
+		for j, r := range term {
+			if unicode.ToLower(s[i+j]) != unicode.ToLower(r) {
+				continue outer
+			}
+		}
+		return i
+	}
+
+	return -1
+}
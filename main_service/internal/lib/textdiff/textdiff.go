@@ -0,0 +1,145 @@
+package textdiff
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// * типы строк в hunk'е
+const (
+	OpContext = "context"
+	OpAdd     = "add"
+	OpDelete  = "delete"
+)
+
+type Line struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// * Hunk — непрерывный участок изменений с context строками вокруг.
+// * Номера строк начинаются с 1, как в unified diff.
+type Hunk struct {
+	OldStart int    `json:"old_start"`
+	OldLines int    `json:"old_lines"`
+	NewStart int    `json:"new_start"`
+	NewLines int    `json:"new_lines"`
+	Lines    []Line `json:"lines"`
+}
+
+// * Compute построчно сравнивает a и b и возвращает hunk'и с lines строками контекста.
+// * difflib не принимает ctx, поэтому сравнение идёт в отдельной горутине: с отменой ctx Compute сразу
+// * возвращает его ошибку, а горутина досчитывает в фоне. Её время ограничивает размер текстов.
+func Compute(ctx context.Context, a, b string, lines int) ([]Hunk, error) {
+	done := make(chan []Hunk, 1)
+	go func() { done <- compute(a, b, lines) }()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case hunks := <-done:
+		return hunks, nil
+	}
+}
+
+func compute(a, b string, context int) []Hunk {
+	oldLines, newLines := split(a), split(b)
+
+	m := difflib.NewMatcher(oldLines, newLines)
+
+	var hunks []Hunk
+	for _, group := range m.GetGroupedOpCodes(context) {
+		first, last := group[0], group[len(group)-1]
+
+		h := Hunk{
+			OldStart: start(first.I1, last.I2),
+			OldLines: last.I2 - first.I1,
+			NewStart: start(first.J1, last.J2),
+			NewLines: last.J2 - first.J1,
+		}
+
+		for _, c := range group {
+			if c.Tag == 'e' {
+				h.Lines = appendLines(h.Lines, OpContext, oldLines[c.I1:c.I2])
+				continue
+			}
+			if c.Tag == 'r' || c.Tag == 'd' {
+				h.Lines = appendLines(h.Lines, OpDelete, oldLines[c.I1:c.I2])
+			}
+			if c.Tag == 'r' || c.Tag == 'i' {
+				h.Lines = appendLines(h.Lines, OpAdd, newLines[c.J1:c.J2])
+			}
+		}
+
+		hunks = append(hunks, h)
+	}
+
+	return hunks
+}
+
+// * Unified форматирует hunk'и в unified diff с заголовками fromFile и toFile
+func Unified(hunks []Hunk, fromFile, toFile string) string {
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromFile, toFile)
+
+	for _, h := range hunks {
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", rangeOf(h.OldStart, h.OldLines), rangeOf(h.NewStart, h.NewLines))
+
+		for _, l := range h.Lines {
+			switch l.Op {
+			case OpAdd:
+				sb.WriteByte('+')
+			case OpDelete:
+				sb.WriteByte('-')
+			default:
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(l.Text)
+			sb.WriteByte('\n')
+		}
+	}
+
+	return sb.String()
+}
+
+// * split разбивает текст на строки без завершающих переводов строки
+func split(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func appendLines(dst []Line, op string, lines []string) []Line {
+	for _, l := range lines {
+		dst = append(dst, Line{Op: op, Text: l})
+	}
+
+	return dst
+}
+
+// * start переводит индекс в номер строки; для пустого диапазона unified diff указывает строку перед ним
+func start(i1, i2 int) int {
+	if i1 == i2 {
+		return i1
+	}
+
+	return i1 + 1
+}
+
+func rangeOf(start, length int) string {
+	if length == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start, length)
+}
@@ -0,0 +1,139 @@
+package textdiff
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		context int
+		want    []Hunk
+	}{
+		{name: "both empty", a: "", b: ""},
+		{name: "equal", a: "a\nb\n", b: "a\nb\n", context: 3},
+		{
+			name: "from empty",
+			a:    "",
+			b:    "a\nb\n",
+			want: []Hunk{{OldStart: 0, OldLines: 0, NewStart: 1, NewLines: 2, Lines: []Line{{OpAdd, "a"}, {OpAdd, "b"}}}},
+		},
+		{
+			name: "to empty",
+			a:    "a\nb\n",
+			b:    "",
+			want: []Hunk{{OldStart: 1, OldLines: 2, NewStart: 0, NewLines: 0, Lines: []Line{{OpDelete, "a"}, {OpDelete, "b"}}}},
+		},
+		{
+			// Завершающий перевод строки не делает строку отличной
+			name:    "no trailing newline",
+			a:       "a\nb",
+			b:       "a\nb\n",
+			context: 3,
+		},
+		{
+			name:    "insert only",
+			a:       "a\nb\nc\n",
+			b:       "a\nb\nx\nc\n",
+			context: 1,
+			want: []Hunk{{OldStart: 2, OldLines: 2, NewStart: 2, NewLines: 3, Lines: []Line{
+				{OpContext, "b"}, {OpAdd, "x"}, {OpContext, "c"},
+			}}},
+		},
+		{
+			name:    "delete only",
+			a:       "a\nb\nx\nc\n",
+			b:       "a\nb\nc\n",
+			context: 1,
+			want: []Hunk{{OldStart: 2, OldLines: 3, NewStart: 2, NewLines: 2, Lines: []Line{
+				{OpContext, "b"}, {OpDelete, "x"}, {OpContext, "c"},
+			}}},
+		},
+		{
+			name:    "replace without context",
+			a:       "a\nb\nc\n",
+			b:       "a\ny\nc\n",
+			context: 0,
+			want: []Hunk{{OldStart: 2, OldLines: 1, NewStart: 2, NewLines: 1, Lines: []Line{
+				{OpDelete, "b"}, {OpAdd, "y"},
+			}}},
+		},
+		{
+			// Контекст не выходит за границы текста
+			name:    "context past the edges",
+			a:       "a\nb\n",
+			b:       "x\nb\n",
+			context: 5,
+			want: []Hunk{{OldStart: 1, OldLines: 2, NewStart: 1, NewLines: 2, Lines: []Line{
+				{OpDelete, "a"}, {OpAdd, "x"}, {OpContext, "b"},
+			}}},
+		},
+		{
+			// Изменения дальше 2*context строк друг от друга — разные hunk'и
+			name:    "separate hunks",
+			a:       "1\n2\n3\n4\n5\n6\n7\n",
+			b:       "x\n2\n3\n4\n5\n6\ny\n",
+			context: 1,
+			want: []Hunk{
+				{OldStart: 1, OldLines: 2, NewStart: 1, NewLines: 2, Lines: []Line{{OpDelete, "1"}, {OpAdd, "x"}, {OpContext, "2"}}},
+				{OldStart: 6, OldLines: 2, NewStart: 6, NewLines: 2, Lines: []Line{{OpContext, "6"}, {OpDelete, "7"}, {OpAdd, "y"}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compute(context.Background(), tt.a, tt.b, tt.context)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compute() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	a := strings.Repeat("a\nb\n", 20000)
+	b := strings.Repeat("b\na\n", 20000)
+
+	if _, err := Compute(ctx, a, b, 3); !errors.Is(err, context.Canceled) {
+		t.Errorf("Compute() error = %v, want Canceled", err)
+	}
+}
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{name: "no changes", a: "a\n", b: "a\n", want: ""},
+		{name: "from empty", a: "", b: "a\n", want: "--- a\n+++ b\n@@ -0,0 +1 @@\n+a\n"},
+		{name: "to empty", a: "a\nb\n", b: "", want: "--- a\n+++ b\n@@ -1,2 +0,0 @@\n-a\n-b\n"},
+		{name: "replace", a: "a\nb\nc\n", b: "a\ny\nc\n", want: "--- a\n+++ b\n@@ -1,3 +1,3 @@\n a\n-b\n+y\n c\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hunks, err := Compute(context.Background(), tt.a, tt.b, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := Unified(hunks, "a", "b"); got != tt.want {
+				t.Errorf("Unified() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+)
+
+// * сколько байт тела запроса попадает в журнал; тела админских запросов — короткий JSON
+const maxParams = 4096
+
+// * таймаут записи в журнал, не зависит от того, ушёл ли клиент
+const writeTimeout = 2 * time.Second
+
+type Store interface {
+	AddAudit(ctx context.Context, e *models.AuditEntry) error
+}
+
+// * middleware, который записывает в журнал каждое обращение администратора:
+// * кто, какой маршрут, хэш текста, параметры запроса и код ответа.
+// * Ставится после auth.Admin.
+func New(log *slog.Logger, store Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			const op = "middleware.audit"
+
+			params := r.URL.RawQuery
+			if r.Body != nil && r.Body != http.NoBody {
+				body, _ := io.ReadAll(io.LimitReader(r.Body, maxParams))
+				r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+
+				if len(body) > 0 {
+					if params != "" {
+						params += " "
+					}
+					params += string(body)
+				}
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			// URL параметры и шаблон маршрута известны только после роутинга
+			entry := &models.AuditEntry{
+				Action:    r.Method + " " + chi.RouteContext(r.Context()).RoutePattern(),
+				Hash:      chi.URLParam(r, "hash"),
+				Params:    params,
+				Status:    max(ww.Status(), http.StatusOK),
+				RequestID: middleware.GetReqID(r.Context()),
+				CreatedAt: time.Now().UTC(),
+			}
+			if user, ok := auth.UserFromContext(r.Context()); ok {
+				entry.AdminID = user.ID
+			}
+
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), writeTimeout)
+			defer cancel()
+
+			if err := store.AddAudit(ctx, entry); err != nil {
+				log.Error("failed to write audit log",
+					slog.String("op", op),
+					slog.String("request_id", entry.RequestID),
+					slog.String("action", entry.Action),
+					sl.Err(err),
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"main_service/internal/lib/api/apikey"
+	"main_service/internal/lib/api/client"
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Users interface {
+	UserByAPIKey(ctx context.Context, keyHash string) (*models.User, error)
+}
+
+type ctxKey struct{}
+
+// * middleware, который по API ключу определяет пользователя и кладёт его в контекст.
+// * Запрос без ключа пропускается только при allowAnonymous, неверный ключ всегда отклоняется.
+func New(log *slog.Logger, users Users, allowAnonymous bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			const op = "middleware.auth"
+
+			key := apikey.FromRequest(r)
+			if key == "" {
+				if !allowAnonymous {
+					unauthorized(w, r, "API key is required")
+					return
+				}
+
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			user, err := users.UserByAPIKey(r.Context(), apikey.Hash(key))
+			if err != nil {
+				if errors.Is(err, storage.ErrAPIKeyNotFound) {
+					unauthorized(w, r, "Invalid API key")
+					return
+				}
+
+				log.Error("failed to authenticate",
+					slog.String("op", op),
+					slog.String("request_id", middleware.GetReqID(r.Context())),
+					sl.Err(err),
+				)
+
+				render.Status(r, http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("Internal error"))
+
+				return
+			}
+
+			trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("enduser.id", strconv.FormatInt(user.ID, 10)))
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKey{}, user)))
+		})
+	}
+}
+
+// * Required пропускает только аутентифицированные запросы. Ставится после New.
+func Required(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := UserFromContext(r.Context()); !ok {
+			unauthorized(w, r, "API key is required")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// * Admin пропускает только администраторов (users.is_admin). Ставится после New.
+func Admin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := UserFromContext(r.Context())
+		if !ok {
+			unauthorized(w, r, "API key is required")
+			return
+		}
+
+		if !user.IsAdmin {
+			render.Status(r, http.StatusForbidden)
+			render.JSON(w, r, resp.Error("Forbidden"))
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// * UserFromContext возвращает пользователя, которого положил middleware
+func UserFromContext(ctx context.Context) (*models.User, bool) {
+	user, ok := ctx.Value(ctxKey{}).(*models.User)
+	return user, ok
+}
+
+// * ClientKey возвращает идентификатор клиента для лимитов: пользователя, если его положил New, иначе IP.
+// * Непроверенный API ключ идентификатором быть не может: новый случайный ключ давал бы новый bucket.
+func ClientKey(r *http.Request) string {
+	if user, ok := UserFromContext(r.Context()); ok {
+		return "user:" + strconv.FormatInt(user.ID, 10)
+	}
+
+	return "ip:" + client.IP(r)
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, msg string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="pastebin"`)
+
+	render.Status(r, http.StatusUnauthorized)
+	render.JSON(w, r, resp.Error(msg))
+}
@@ -0,0 +1,71 @@
+package enumGuard
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/middleware/auth"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+type Tracker interface {
+	BanTTL(ctx context.Context, client string) (time.Duration, error)
+	RecordMiss(ctx context.Context, client string, window time.Duration, threshold int, baseBan, maxBan time.Duration) (time.Duration, error)
+}
+
+// * middleware, защищающий от перебора хэшей.
+// * Считает ответы 404/410 на клиента; после maxMisses за window клиент банится,
+// * и каждый следующий бан в течение суток вдвое длиннее предыдущего (до maxBan).
+func New(log *slog.Logger, tracker Tracker, window time.Duration, maxMisses int, baseBan, maxBan time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			const op = "middleware.enumGuard"
+
+			log := log.With(
+				slog.String("op", op),
+				slog.String("request_id", middleware.GetReqID(r.Context())),
+			)
+
+			key := auth.ClientKey(r)
+
+			ban, err := tracker.BanTTL(r.Context(), key)
+			if err != nil {
+				log.Error("failed to check ban", sl.Err(err))
+			}
+
+			if ban > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(ban.Seconds()))))
+
+				render.Status(r, http.StatusTooManyRequests)
+				render.JSON(w, r, resp.Error("Too many requests"))
+
+				return
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			if ww.Status() != http.StatusNotFound && ww.Status() != http.StatusGone {
+				return
+			}
+
+			ban, err = tracker.RecordMiss(r.Context(), key, window, maxMisses, baseBan, maxBan)
+			if err != nil {
+				log.Error("failed to record miss", sl.Err(err))
+				return
+			}
+
+			if ban > 0 {
+				log.Warn("Client banned for hash enumeration", slog.String("client", key), slog.Duration("ban", ban))
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package rateLimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	resp "main_service/internal/lib/api/response"
+	sl "main_service/internal/lib/logger"
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+type Limiter interface {
+	TakeToken(ctx context.Context, key string, rate float64, burst int) (models.RateLimitResult, error)
+}
+
+// * middleware, ограничивающий частоту запросов token bucket'ом в redis.
+// * Bucket выбирается по пользователю, которого положил auth.New, иначе по IP клиента:
+// * до auth.New лимит всегда по IP. scope разделяет лимиты разных эндпоинтов.
+func New(log *slog.Logger, limiter Limiter, scope string, rate float64, burst int) func(http.Handler) http.Handler {
+	policy := fmt.Sprintf("%d;w=%d", burst, int(math.Ceil(float64(burst)/rate)))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			const op = "middleware.rateLimit"
+
+			res, err := limiter.TakeToken(r.Context(), scope+":"+auth.ClientKey(r), rate, burst)
+			if err != nil {
+				// Недоступный redis не должен ронять API — пропускаем запрос
+				log.Error("failed to take rate limit token",
+					slog.String("op", op),
+					slog.String("request_id", middleware.GetReqID(r.Context())),
+					sl.Err(err),
+				)
+
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			w.Header().Set("RateLimit-Policy", policy)
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(burst))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(seconds(res.Reset)))
+
+			if !res.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(seconds(res.RetryAfter)))
+
+				render.Status(r, http.StatusTooManyRequests)
+				render.JSON(w, r, resp.Error("Too many requests"))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// * seconds округляет длительность вверх до целых секунд, как того требуют заголовки
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
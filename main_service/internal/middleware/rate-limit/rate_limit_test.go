@@ -0,0 +1,98 @@
+package rateLimit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"main_service/internal/middleware/auth"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+)
+
+// * fakeLimiter пропускает burst запросов на ключ и запоминает ключи
+type fakeLimiter struct {
+	taken map[string]int
+}
+
+func (l *fakeLimiter) TakeToken(ctx context.Context, key string, rate float64, burst int) (models.RateLimitResult, error) {
+	l.taken[key]++
+
+	return models.RateLimitResult{
+		Allowed:    l.taken[key] <= burst,
+		Remaining:  max(burst-l.taken[key], 0),
+		RetryAfter: time.Second,
+	}, nil
+}
+
+type fakeUsers struct{}
+
+func (fakeUsers) UserByAPIKey(ctx context.Context, keyHash string) (*models.User, error) {
+	return nil, storage.ErrAPIKeyNotFound
+}
+
+func TestRandomAPIKeysShareIPBucket(t *testing.T) {
+	limiter := &fakeLimiter{taken: make(map[string]int)}
+	log := slog.New(slog.DiscardHandler)
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	// Как в роутере: лимит по IP стоит до проверки ключа
+	h := New(log, limiter, "all", 1, 3)(auth.New(log, fakeUsers{}, true)(ok))
+
+	codes := make([]int, 0, 5)
+	for i := 0; i < 5; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/text/abc", nil)
+		req.RemoteAddr = "203.0.113.7:5000"
+		req.Header.Set("X-API-Key", "random-"+strconv.Itoa(i))
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		codes = append(codes, rec.Code)
+	}
+
+	if len(limiter.taken) != 1 || limiter.taken["all:ip:203.0.113.7"] != 5 {
+		t.Errorf("buckets = %v, want one IP bucket", limiter.taken)
+	}
+
+	want := []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests, http.StatusTooManyRequests}
+	for i := range want {
+		if codes[i] != want[i] {
+			t.Errorf("request %d status = %d, want %d", i, codes[i], want[i])
+		}
+	}
+}
+
+type knownUsers struct{}
+
+func (knownUsers) UserByAPIKey(ctx context.Context, keyHash string) (*models.User, error) {
+	if keyHash == "" {
+		return nil, errors.New("empty key hash")
+	}
+
+	return &models.User{ID: 42}, nil
+}
+
+func TestAuthenticatedUserBucket(t *testing.T) {
+	limiter := &fakeLimiter{taken: make(map[string]int)}
+	log := slog.New(slog.DiscardHandler)
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := auth.New(log, knownUsers{}, true)(New(log, limiter, "get", 1, 10)(ok))
+
+	for _, addr := range []string{"203.0.113.7:5000", "198.51.100.1:6000"} {
+		req := httptest.NewRequest(http.MethodGet, "/text/abc", nil)
+		req.RemoteAddr = addr
+		req.Header.Set("X-API-Key", "valid")
+
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if len(limiter.taken) != 1 || limiter.taken["get:user:42"] != 2 {
+		t.Errorf("buckets = %v, want one user bucket", limiter.taken)
+	}
+}
@@ -0,0 +1,41 @@
+package requestTracing
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// * middleware, который открывает HTTP span на каждый запрос.
+// * Должен стоять после middleware.RequestID, чтобы request_id попал в атрибуты span.
+func New() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			span := trace.SpanFromContext(r.Context())
+
+			if reqID := middleware.GetReqID(r.Context()); reqID != "" {
+				span.SetAttributes(attribute.String("request_id", reqID))
+			}
+
+			next.ServeHTTP(w, r)
+
+			// Шаблон маршрута известен только после того, как chi отработал
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				if pattern := rctx.RoutePattern(); pattern != "" {
+					span.SetName(r.Method + " " + pattern)
+					span.SetAttributes(attribute.String("http.route", pattern))
+				}
+			}
+		})
+
+		return otelhttp.NewHandler(inner, "http.server",
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return r.Method
+			}),
+		)
+	}
+}
@@ -0,0 +1,55 @@
+package textService
+
+import (
+	"context"
+	"errors"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"time"
+)
+
+// * ListPastes возвращает страницу текстов по фильтрам администратора и их общее количество
+func (s *TextOperator) ListPastes(ctx context.Context, f models.PasteFilter, limit, offset int) ([]models.Paste, int, error) {
+	return s.mysql.ListPastes(ctx, f, limit, offset)
+}
+
+// * ExpireText сразу делает текст истёкшим. Данные удалит ближайшая очистка.
+func (s *TextOperator) ExpireText(ctx context.Context, hash string) error {
+	if err := s.mysql.UpdateExpiresAt(ctx, hash, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	return s.dropCache(ctx, hash)
+}
+
+// * ExtendText продлевает текст на days дней, в том числе уже истёкший, но ещё не удалённый
+func (s *TextOperator) ExtendText(ctx context.Context, hash string, days int) (time.Time, error) {
+	expiresAt, err := s.mysql.ExtendExpiresAt(ctx, hash, days)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// В кэше лежит старое время истечения, а истёкший текст мог попасть в negative cache
+	if err := s.dropCache(ctx, hash); err != nil {
+		return time.Time{}, err
+	}
+
+	if err := s.redis.DeleteMiss(ctx, hash); err != nil {
+		return time.Time{}, err
+	}
+
+	return expiresAt, nil
+}
+
+// * PurgeText удаляет текст отовсюду: MySQL, MinIO, кэш, рейтинг популярности и negative cache
+func (s *TextOperator) PurgeText(ctx context.Context, hash string) error {
+	if _, err := s.mysql.GetByHash(ctx, hash); err != nil && !errors.Is(err, storage.ErrTTLIsExpired) {
+		return err
+	}
+
+	if err := s.DeleteText(ctx, hash); err != nil {
+		return err
+	}
+
+	return s.redis.DeleteMiss(ctx, hash)
+}
@@ -0,0 +1,38 @@
+package textService
+
+import (
+	"context"
+	"main_service/internal/models"
+)
+
+// * GetEncoded возвращает содержимое c текста hash, сжатое алгоритмом encoding функцией encode.
+// * Сжатые данные популярных текстов хранятся в redis рядом с содержимым под ключом с digest,
+// * поэтому никогда не относятся к другой ревизии и удаляются вместе с текстом.
+// * Доступ к c уже проверил GetContent, просмотр здесь не учитывается.
+func (s *TextOperator) GetEncoded(
+	ctx context.Context,
+	hash string,
+	c *models.Content,
+	encoding string,
+	encode func(data string) (string, error),
+) (string, error) {
+	digest := c.Digest
+	if digest == "" {
+		digest = models.Digest(c.Data)
+	}
+	variant := "enc:" + encoding + ":" + digest
+
+	if blob, _, _ := s.redis.View(ctx, hash, variant); blob != "" {
+		return blob, nil
+	}
+
+	blob, err := encode(c.Data)
+	if err != nil {
+		return "", err
+	}
+
+	// Сохраняется, только если само содержимое лежит в кэше
+	_ = s.redis.SaveView(ctx, hash, variant, blob)
+
+	return blob, nil
+}
@@ -0,0 +1,149 @@
+package textService
+
+import (
+	"context"
+	"fmt"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"strings"
+)
+
+// * fileKey возвращает ключ объекта i-го файла многофайлового текста в MinIO.
+// * Имя файла в ключ не попадает, оно хранится только в манифесте.
+func fileKey(hash string, i int) string {
+	return fmt.Sprintf("%s/files/%d", hash, i)
+}
+
+// * saveFiles сохраняет многофайловый текст: каждый файл — отдельный объект под префиксом hash,
+// * манифест — в MySQL. Первая ревизия ссылается на первый файл, поэтому GET /text/{hash}
+// * и кэш работают с ним как с обычным текстом.
+func (s *TextOperator) saveFiles(ctx context.Context, hash string, in models.PasteInput) error {
+	p := models.Paste{}
+
+	files := make([]models.File, 0, len(in.Files))
+	for i, f := range in.Files {
+		lang, confidence := detectLanguage(f.Language, f.Name, f.Content)
+
+		file := models.File{
+			Name:      f.Name,
+			Language:  lang,
+			ObjectKey: fileKey(hash, i),
+			Size:      int64(len(f.Content)),
+		}
+
+		// Язык текста — язык первого файла, его и показывает GET /text/{hash}
+		if i == 0 {
+			p.Language, p.LanguageConfidence = lang, confidence
+		}
+
+		if err := s.minio.SaveStringAsFile(ctx, file.ObjectKey, f.Content); err != nil {
+			return err
+		}
+
+		files = append(files, file)
+	}
+
+	rev := &models.Revision{
+		Hash:        hash,
+		Rev:         1,
+		ObjectKey:   files[0].ObjectKey,
+		ContentType: models.ContentTypeText,
+		Size:        files[0].Size,
+		Digest:      models.Digest(in.Files[0].Content),
+		AuthorID:    in.OwnerID,
+	}
+
+	if err := s.publish(ctx, in, p, rev, files); err != nil {
+		return err
+	}
+
+	contents := make([]string, 0, len(in.Files))
+	for _, f := range in.Files {
+		contents = append(contents, f.Content)
+	}
+
+	return s.index(ctx, hash, strings.Join(contents, "\n"))
+}
+
+// * Files возвращает манифест файлов текста, если viewer имеет к нему доступ
+func (s *TextOperator) Files(ctx context.Context, hash string, viewer models.Viewer) ([]models.File, error) {
+	if _, err := s.readablePaste(ctx, hash, viewer); err != nil {
+		return nil, err
+	}
+
+	return s.mysql.Files(ctx, hash)
+}
+
+// * FileText возвращает файл name многофайлового текста вместе с содержимым
+func (s *TextOperator) FileText(ctx context.Context, hash, name string, viewer models.Viewer) (*models.File, string, error) {
+	if _, err := s.readablePaste(ctx, hash, viewer); err != nil {
+		return nil, "", err
+	}
+
+	f, err := s.mysql.File(ctx, hash, name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	content, err := s.minio.GetString(ctx, f.ObjectKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return f, content, nil
+}
+
+// * ReadFiles по очереди передаёт в fn все файлы текста с содержимым,
+// * чтобы архив можно было писать в ответ, не держа в памяти все файлы сразу.
+// * Обычный текст отдаётся как единственный файл <hash>.txt, бинарный — <hash>.bin.
+func (s *TextOperator) ReadFiles(ctx context.Context, hash string, viewer models.Viewer, fn func(f models.File, content string) error) error {
+	paste, err := s.readablePaste(ctx, hash, viewer)
+	if err != nil {
+		return err
+	}
+
+	files, err := s.mysql.Files(ctx, hash)
+	if err != nil {
+		return err
+	}
+
+	if len(files) == 0 {
+		name := hash + ".txt"
+		if !models.IsText(paste.ContentType) {
+			name = hash + ".bin"
+		}
+		files = []models.File{{Name: name, ObjectKey: paste.ObjectKey}}
+	}
+
+	for _, f := range files {
+		content, err := s.minio.GetString(ctx, f.ObjectKey)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(f, content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// * readablePaste возвращает метаданные текста, если viewer имеет к нему доступ.
+// * Чужой приватный текст неотличим от несуществующего и после истечения.
+func (s *TextOperator) readablePaste(ctx context.Context, hash string, viewer models.Viewer) (*models.Paste, error) {
+	paste, err := s.mysql.GetByHash(ctx, hash)
+	if err != nil {
+		if paste != nil && !paste.CanRead(viewer) {
+			return nil, storage.ErrTextNotFound
+		}
+
+		return nil, err
+	}
+
+	if err := readable(paste, viewer); err != nil {
+		return nil, err
+	}
+
+	return paste, nil
+}
@@ -0,0 +1,142 @@
+package textService
+
+import (
+	"context"
+	"main_service/internal/models"
+)
+
+// * ForkText создаёт новый текст из ревизии rev (0 — текущей) текста hash и возвращает его хэш.
+// * Пустой in.Text означает форк без изменений. Такой форк, как и форк с тем же содержимым,
+// * не копирует объект в MinIO, а ссылается на объект исходной ревизии. Форк многофайлового текста
+// * без изменений получает копию манифеста, ссылающуюся на те же объекты файлов.
+// * Видимость по умолчанию наследуется от исходного текста.
+func (s *TextOperator) ForkText(ctx context.Context, hash string, rev int, viewer models.Viewer, in models.PasteInput) (string, error) {
+	src, err := s.readablePaste(ctx, hash, viewer)
+	if err != nil {
+		return "", err
+	}
+
+	if rev == 0 {
+		rev = src.CurrentRev
+	}
+
+	srcRev, err := s.mysql.Revision(ctx, hash, rev)
+	if err != nil {
+		return "", err
+	}
+
+	shared := in.Text == ""
+	if !shared {
+		if err := s.checkSecrets(&in); err != nil {
+			return "", err
+		}
+
+		text, err := s.minio.GetString(ctx, srcRev.ObjectKey)
+		if err != nil {
+			return "", err
+		}
+		shared = text == in.Text
+	}
+
+	var files []models.File
+	if shared {
+		srcFiles, err := s.mysql.Files(ctx, hash)
+		if err != nil {
+			return "", err
+		}
+
+		// Манифест описывает ревизию, которая ссылается на первый файл
+		if len(srcFiles) > 0 && srcFiles[0].ObjectKey == srcRev.ObjectKey {
+			files = srcFiles
+		}
+	}
+
+	forkHash, err := s.kafka.ReadMessage(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	fork := &models.Revision{
+		Hash:        forkHash,
+		Rev:         1,
+		ObjectKey:   srcRev.ObjectKey,
+		ContentType: srcRev.ContentType,
+		Size:        srcRev.Size,
+		Digest:      srcRev.Digest,
+		AuthorID:    in.OwnerID,
+	}
+
+	p := models.Paste{
+		ParentHash:         hash,
+		Language:           src.Language,
+		LanguageConfidence: src.LanguageConfidence,
+		Filename:           src.Filename,
+	}
+
+	if !shared {
+		if in.Filename != "" {
+			p.Filename = in.Filename
+		}
+		p.Language, p.LanguageConfidence = detectLanguage(in.Language, p.Filename, in.Text)
+
+		fork.ObjectKey = objectKey(forkHash, 1)
+		fork.ContentType = models.ContentTypeText
+		fork.Size = int64(len(in.Text))
+		fork.Digest = models.Digest(in.Text)
+
+		if err := s.minio.SaveStringAsFile(ctx, fork.ObjectKey, in.Text); err != nil {
+			return "", err
+		}
+	}
+
+	if in.Visibility == "" {
+		in.Visibility = src.Visibility
+	}
+
+	if err := s.publish(ctx, in, p, fork, files); err != nil {
+		return "", err
+	}
+
+	if shared {
+		return forkHash, s.mysql.CopyIndex(ctx, hash, forkHash)
+	}
+
+	return forkHash, s.index(ctx, forkHash, in.Text)
+}
+
+// * Metadata возвращает метаданные текста и дерево его форков, видимых viewer'у.
+// * Форки, которые viewer не может видеть в списках, скрываются вместе с их потомками.
+func (s *TextOperator) Metadata(ctx context.Context, hash string, viewer models.Viewer) (*models.Paste, []models.ForkNode, error) {
+	paste, err := s.readablePaste(ctx, hash, viewer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	forks, err := s.mysql.Forks(ctx, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	children := make(map[string][]models.Paste)
+	for _, f := range forks {
+		if f.CanList(viewer) {
+			children[f.ParentHash] = append(children[f.ParentHash], f)
+		}
+	}
+
+	return paste, forkTree(children, hash), nil
+}
+
+// * forkTree собирает дерево потомков parent
+func forkTree(children map[string][]models.Paste, parent string) []models.ForkNode {
+	var nodes []models.ForkNode
+	for _, c := range children[parent] {
+		nodes = append(nodes, models.ForkNode{
+			Hash:      c.Hash,
+			CreatedAt: c.CreatedAt,
+			Forks:     forkTree(children, c.Hash),
+		})
+	}
+
+	return nodes
+}
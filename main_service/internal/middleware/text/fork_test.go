@@ -0,0 +1,93 @@
+package textService
+
+import (
+	"context"
+	"main_service/internal/models"
+	"testing"
+)
+
+func TestForkText(t *testing.T) {
+	ctx := context.Background()
+
+	s, f := newTestService()
+
+	plain, err := s.SaveText(ctx, models.PasteInput{Text: "hello", TTLDays: 1})
+	if err != nil {
+		t.Fatalf("SaveText() error = %v", err)
+	}
+
+	gist, err := s.SaveText(ctx, models.PasteInput{TTLDays: 1, Files: []models.FileInput{
+		{Name: "main.go", Content: "package main"},
+		{Name: "README.md", Content: "# readme"},
+	}})
+	if err != nil {
+		t.Fatalf("SaveText() error = %v", err)
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		fork, err := s.ForkText(ctx, plain, 0, models.Viewer{}, models.PasteInput{TTLDays: 1})
+		if err != nil {
+			t.Fatalf("ForkText() error = %v", err)
+		}
+
+		if got, want := f.mysql.pastes[fork].ObjectKey, f.mysql.pastes[plain].ObjectKey; got != want {
+			t.Errorf("fork object = %q, want shared %q", got, want)
+		}
+		if got := f.mysql.pastes[fork].ParentHash; got != plain {
+			t.Errorf("fork parent = %q, want %q", got, plain)
+		}
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		fork, err := s.ForkText(ctx, plain, 0, models.Viewer{}, models.PasteInput{Text: "bye", TTLDays: 1})
+		if err != nil {
+			t.Fatalf("ForkText() error = %v", err)
+		}
+
+		key := f.mysql.pastes[fork].ObjectKey
+		if key == f.mysql.pastes[plain].ObjectKey {
+			t.Errorf("changed fork shares object %q", key)
+		}
+		if got := f.minio.objects[key]; got != "bye" {
+			t.Errorf("fork content = %q, want %q", got, "bye")
+		}
+	})
+
+	t.Run("gist", func(t *testing.T) {
+		fork, err := s.ForkText(ctx, gist, 0, models.Viewer{}, models.PasteInput{TTLDays: 1})
+		if err != nil {
+			t.Fatalf("ForkText() error = %v", err)
+		}
+
+		files, err := s.Files(ctx, fork, models.Viewer{})
+		if err != nil {
+			t.Fatalf("Files() error = %v", err)
+		}
+
+		src := f.mysql.files[gist]
+		if len(files) != len(src) {
+			t.Fatalf("fork has %d files, want %d", len(files), len(src))
+		}
+		for i := range files {
+			if files[i] != src[i] {
+				t.Errorf("fork file %d = %+v, want %+v", i, files[i], src[i])
+			}
+		}
+
+		var contents []string
+		err = s.ReadFiles(ctx, fork, models.Viewer{}, func(_ models.File, content string) error {
+			contents = append(contents, content)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("ReadFiles() error = %v", err)
+		}
+		if len(contents) != 2 || contents[0] != "package main" || contents[1] != "# readme" {
+			t.Errorf("fork contents = %q", contents)
+		}
+
+		if n := f.minio.count("SaveStringAsFile"); n != 4 {
+			t.Errorf("SaveStringAsFile called %d times, want 4: forks without changes must not copy objects", n)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package textService
+
+import (
+	"context"
+	"log/slog"
+	"main_service/internal/lib/lru"
+	"main_service/internal/models"
+	"time"
+)
+
+// * flushTimeout — сколько ждать redis при сбросе накопленных просмотров
+const flushTimeout = 5 * time.Second
+
+// * WithLocalCache включает L1 кэш в памяти процесса перед redis: до maxBytes содержимого,
+// * каждая запись живёт не дольше ttl. Просмотры из кэша копятся локально, их сбрасывает StartLocalCache.
+// * Инвалидация через pub/sub может потеряться при переподключении к redis, тогда запись доживает до ttl.
+func (s *TextOperator) WithLocalCache(maxBytes int64, ttl time.Duration, log *slog.Logger) *TextOperator {
+	s.local = lru.New[*models.Content](maxBytes)
+	s.localTTL = ttl
+	s.views = make(map[string]int64)
+	s.log = log
+
+	return s
+}
+
+// * StartLocalCache раз в flushEvery сбрасывает накопленные просмотры в redis и слушает инвалидации
+// * от других реплик, пока не отменён ctx. Остаток просмотров при остановке сбрасывает FlushViews.
+func (s *TextOperator) StartLocalCache(ctx context.Context, flushEvery time.Duration) {
+	if s.local == nil {
+		return
+	}
+
+	go func() {
+		for hash := range s.redis.Invalidations(ctx) {
+			s.local.Remove(hash)
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(flushEvery)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.FlushViews(ctx)
+			}
+		}
+	}()
+}
+
+// * cachedContent ищет текущую ревизию hash сначала в L1, затем в redis.
+// * Найденное в redis кладётся в L1 не дольше localTTL и не дольше жизни самого текста.
+func (s *TextOperator) cachedContent(ctx context.Context, hash string) *models.Content {
+	if s.local != nil {
+		if c, ok := s.local.Get(hash); ok {
+			return c
+		}
+	}
+
+	c, _ := s.redis.Content(ctx, hash)
+	if c == nil || s.local == nil {
+		return c
+	}
+
+	expiresAt := time.Now().Add(s.localTTL)
+	if c.ExpiresAt.Before(expiresAt) {
+		expiresAt = c.ExpiresAt
+	}
+	s.local.Add(hash, c, int64(len(c.Data)), expiresAt)
+
+	return c
+}
+
+// * countView учитывает просмотр закэшированного текста: при включённом L1 — локально, иначе сразу в redis
+func (s *TextOperator) countView(ctx context.Context, hash string) error {
+	if s.local == nil {
+		_, err := s.redis.IncPopularity(ctx, hash)
+		return err
+	}
+
+	s.viewsMu.Lock()
+	s.views[hash]++
+	s.viewsMu.Unlock()
+
+	return nil
+}
+
+// * FlushViews отправляет накопленные просмотры в redis. Если redis недоступен, просмотры
+// * возвращаются в буфер до следующей попытки.
+func (s *TextOperator) FlushViews(ctx context.Context) {
+	if s.local == nil {
+		return
+	}
+
+	s.viewsMu.Lock()
+	views := s.views
+	s.views = make(map[string]int64, len(views))
+	s.viewsMu.Unlock()
+
+	if len(views) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, flushTimeout)
+	defer cancel()
+
+	if err := s.redis.AddViews(ctx, views); err != nil {
+		s.log.Error("failed to flush views", slog.Int("pastes", len(views)), slog.Any("error", err))
+
+		s.viewsMu.Lock()
+		for hash, n := range views {
+			s.views[hash] += n
+		}
+		s.viewsMu.Unlock()
+	}
+}
+
+// * dropCache удаляет текст из L1 этой реплики и из redis; redis оповещает остальные реплики
+func (s *TextOperator) dropCache(ctx context.Context, hash string) error {
+	if s.local != nil {
+		s.local.Remove(hash)
+	}
+
+	return s.redis.DeleteText(ctx, hash)
+}
@@ -0,0 +1,130 @@
+package textService
+
+import (
+	"context"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"time"
+)
+
+// * причина, которую видят читатели автоматически скрытого текста
+const autoHideReason = "Hidden pending moderation review"
+
+// * WithAutoHide включает автоматическое скрытие текста после n жалоб от разных читателей; 0 — не скрывать
+func (s *TextOperator) WithAutoHide(n int) *TextOperator {
+	s.autoHideReports = n
+
+	return s
+}
+
+// * readable проверяет, что viewer может читать текст p.
+// * Чужой приватный текст неотличим от несуществующего, скрытый модератором отдаёт причину.
+func readable(p *models.Paste, viewer models.Viewer) error {
+	if !p.CanRead(viewer) {
+		return storage.ErrTextNotFound
+	}
+
+	if p.Moderation != "" {
+		return &storage.TakenDownError{Reason: p.ModerationReason}
+	}
+
+	return nil
+}
+
+// * Report сохраняет жалобу viewer'а на текст hash. Жалоба считается один раз на жалобщика (report.ReporterKey).
+// * После autoHideReports разных жалобщиков текст скрывается до решения модератора.
+func (s *TextOperator) Report(ctx context.Context, hash string, viewer models.Viewer, report models.Report) error {
+	if _, err := s.readablePaste(ctx, hash, viewer); err != nil {
+		return err
+	}
+
+	report.Hash = hash
+	report.CreatedAt = time.Now().UTC()
+
+	n, err := s.mysql.AddReport(ctx, &report)
+	if err != nil {
+		return err
+	}
+
+	if s.autoHideReports == 0 || n < s.autoHideReports {
+		return nil
+	}
+
+	if err := s.mysql.SetModeration(ctx, hash, models.ModerationHidden, autoHideReason); err != nil {
+		return err
+	}
+
+	return s.dropCache(ctx, hash)
+}
+
+// * ReportedPastes возвращает очередь модерации и её полный размер
+func (s *TextOperator) ReportedPastes(ctx context.Context, limit, offset int) ([]models.ReportedPaste, int, error) {
+	return s.mysql.ReportedPastes(ctx, limit, offset)
+}
+
+// * ReviewPaste возвращает текст для модератора вместе с жалобами и содержимым текущей ревизии,
+// * в том числе скрытого текста. У удалённого модератором текста содержимого нет, content — nil.
+func (s *TextOperator) ReviewPaste(ctx context.Context, hash string) (*models.Paste, []models.Report, *models.Content, error) {
+	paste, err := s.mysql.GetByHash(ctx, hash)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	reports, err := s.mysql.Reports(ctx, hash)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if paste.Moderation == models.ModerationRemoved || paste.ObjectKey == "" {
+		return paste, reports, nil, nil
+	}
+
+	data, err := s.minio.GetString(ctx, paste.ObjectKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return paste, reports, &models.Content{Data: data, ContentType: paste.ContentType, Language: paste.Language}, nil
+}
+
+// * TakeDown удаляет содержимое текста из redis, MySQL и MinIO, оставляя метаданные:
+// * дальше GET отвечает 451 с reason. Объекты, на которые ссылаются форки, в MinIO остаются.
+func (s *TextOperator) TakeDown(ctx context.Context, hash, reason string) error {
+	keys, err := s.objectKeys(ctx, hash)
+	if err != nil {
+		return err
+	}
+
+	if err := s.mysql.TakeDown(ctx, hash, reason); err != nil {
+		return err
+	}
+
+	if err := s.dropCache(ctx, hash); err != nil {
+		return err
+	}
+
+	if err := s.redis.Delete(ctx, hash); err != nil {
+		return err
+	}
+
+	return s.deleteUnreferenced(ctx, keys)
+}
+
+// * DismissReports отклоняет жалобы: удаляет их и возвращает скрытый текст читателям.
+// * Удалённый модератором текст восстановить нельзя.
+func (s *TextOperator) DismissReports(ctx context.Context, hash string) error {
+	paste, err := s.mysql.GetByHash(ctx, hash)
+	if err != nil {
+		return err
+	}
+
+	if paste.Moderation == models.ModerationRemoved {
+		return &storage.TakenDownError{Reason: paste.ModerationReason}
+	}
+
+	if err := s.mysql.DeleteReports(ctx, hash); err != nil {
+		return err
+	}
+
+	return s.mysql.SetModeration(ctx, hash, "", "")
+}
@@ -0,0 +1,92 @@
+package textService
+
+import (
+	"context"
+	"main_service/internal/lib/language"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"time"
+)
+
+// * UpdateOwnText сохраняет text как новую ревизию текста ownerID и возвращает её номер.
+// * Старые ревизии не меняются. Многофайловый текст так изменить нельзя, манифест разошёлся бы
+// * с содержимым: для него возвращается storage.ErrHasFiles.
+func (s *TextOperator) UpdateOwnText(ctx context.Context, hash string, ownerID int64, text string) (int, error) {
+	paste, err := s.ownPaste(ctx, hash, ownerID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Новая ревизия не должна возвращать текст, скрытый модератором
+	if paste.Moderation != "" {
+		return 0, &storage.TakenDownError{Reason: paste.ModerationReason}
+	}
+
+	files, err := s.mysql.Files(ctx, hash)
+	if err != nil {
+		return 0, err
+	}
+	if len(files) > 0 {
+		return 0, storage.ErrHasFiles
+	}
+
+	in := models.PasteInput{Text: text, OwnerID: ownerID}
+	if err := s.checkSecrets(&in); err != nil {
+		return 0, err
+	}
+	text = in.Text
+
+	n, err := s.mysql.ReserveRevision(ctx, hash)
+	if err != nil {
+		return 0, err
+	}
+
+	rev := &models.Revision{
+		Hash:        hash,
+		Rev:         n,
+		ObjectKey:   objectKey(hash, n),
+		ContentType: models.ContentTypeText,
+		Size:        int64(len(text)),
+		Digest:      models.Digest(text),
+		AuthorID:    ownerID,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	if err := s.minio.SaveStringAsFile(ctx, rev.ObjectKey, text); err != nil {
+		return 0, err
+	}
+
+	if err := s.mysql.SaveRevision(ctx, rev); err != nil {
+		return 0, err
+	}
+
+	// Язык, указанный автором, сохраняем, определённый автоматически — пересчитываем с той же подсказкой имени файла
+	if paste.LanguageConfidence < language.ConfidenceExplicit {
+		lang, confidence := language.Detect(paste.Filename, text)
+		if lang != paste.Language || confidence != paste.LanguageConfidence {
+			if err := s.mysql.UpdateLanguage(ctx, hash, lang, confidence); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if err := s.index(ctx, hash, text); err != nil {
+		return 0, err
+	}
+
+	// В кэше могла остаться предыдущая ревизия
+	if err := s.dropCache(ctx, hash); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// * Revisions возвращает историю ревизий текста, если viewer имеет к нему доступ
+func (s *TextOperator) Revisions(ctx context.Context, hash string, viewer models.Viewer) ([]models.Revision, error) {
+	if _, err := s.readablePaste(ctx, hash, viewer); err != nil {
+		return nil, err
+	}
+
+	return s.mysql.Revisions(ctx, hash)
+}
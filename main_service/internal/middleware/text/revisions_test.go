@@ -0,0 +1,56 @@
+package textService
+
+import (
+	"context"
+	"errors"
+	"main_service/internal/lib/language"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"testing"
+)
+
+func TestUpdateOwnTextRejectsFiles(t *testing.T) {
+	ctx := context.Background()
+
+	s, f := newTestService()
+
+	gist, err := s.SaveText(ctx, models.PasteInput{TTLDays: 1, OwnerID: 7, Files: []models.FileInput{
+		{Name: "a.txt", Content: "a"},
+		{Name: "b.txt", Content: "b"},
+	}})
+	if err != nil {
+		t.Fatalf("SaveText() error = %v", err)
+	}
+
+	if _, err := s.UpdateOwnText(ctx, gist, 7, "c"); !errors.Is(err, storage.ErrHasFiles) {
+		t.Errorf("UpdateOwnText() error = %v, want ErrHasFiles", err)
+	}
+
+	if n := len(f.mysql.revs[gist]); n != 1 {
+		t.Errorf("gist has %d revisions, want 1", n)
+	}
+}
+
+func TestUpdateOwnTextKeepsFilenameHint(t *testing.T) {
+	ctx := context.Background()
+
+	s, f := newTestService()
+
+	hash, err := s.SaveText(ctx, models.PasteInput{Text: "a: 1\n", Filename: "config.yaml", TTLDays: 1, OwnerID: 7})
+	if err != nil {
+		t.Fatalf("SaveText() error = %v", err)
+	}
+
+	// Без имени файла такой текст не распознаётся
+	if _, err := s.UpdateOwnText(ctx, hash, 7, "just some words\n"); err != nil {
+		t.Fatalf("UpdateOwnText() error = %v", err)
+	}
+
+	p := f.mysql.pastes[hash]
+	if p.Language != "yaml" || p.LanguageConfidence != language.ConfidenceExtension {
+		t.Errorf("language = %q, %v, want yaml, %v", p.Language, p.LanguageConfidence, language.ConfidenceExtension)
+	}
+	if p.Filename != "config.yaml" {
+		t.Errorf("filename = %q, want config.yaml", p.Filename)
+	}
+}
@@ -0,0 +1,77 @@
+package textService
+
+import (
+	"context"
+	"main_service/internal/lib/snippet"
+	"main_service/internal/models"
+	"strings"
+)
+
+const (
+	maxIndexedSize = 64 << 10 // * сколько байт от начала текста попадает в полнотекстовый индекс
+	snippetWidth   = 200      // * длина фрагмента в результатах поиска, в символах
+)
+
+// * Search ищет тексты по содержимому среди публичных и собственных текстов viewer'а
+func (s *TextOperator) Search(ctx context.Context, q string, viewer models.Viewer, limit, offset int) ([]models.SearchHit, error) {
+	hits, err := s.mysql.Search(ctx, q, viewer.UserID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range hits {
+		hits[i].Snippet = snippet.Make(hits[i].Body, q, snippetWidth)
+		hits[i].Body = ""
+	}
+
+	return hits, nil
+}
+
+// * Reindex заново индексирует текущее содержимое текста hash из MinIO.
+// * Использует только MySQL и MinIO, поэтому работает и без Kafka и redis.
+func (s *TextOperator) Reindex(ctx context.Context, hash string) error {
+	paste, err := s.mysql.GetByHash(ctx, hash)
+	if err != nil {
+		return err
+	}
+
+	files, err := s.mysql.Files(ctx, hash)
+	if err != nil {
+		return err
+	}
+
+	if len(files) > 0 {
+		contents := make([]string, 0, len(files))
+		for _, f := range files {
+			content, err := s.minio.GetString(ctx, f.ObjectKey)
+			if err != nil {
+				return err
+			}
+			contents = append(contents, content)
+		}
+
+		return s.index(ctx, hash, strings.Join(contents, "\n"))
+	}
+
+	// Бинарное содержимое не индексируется
+	if !models.IsText(paste.ContentType) {
+		return nil
+	}
+
+	text, err := s.minio.GetString(ctx, paste.ObjectKey)
+	if err != nil {
+		return err
+	}
+
+	return s.index(ctx, hash, text)
+}
+
+// * index кладёт начало text в полнотекстовый индекс
+func (s *TextOperator) index(ctx context.Context, hash, text string) error {
+	if len(text) > maxIndexedSize {
+		// Обрезка могла разрезать многобайтовый символ
+		text = strings.ToValidUTF8(text[:maxIndexedSize], "")
+	}
+
+	return s.mysql.IndexText(ctx, hash, text)
+}
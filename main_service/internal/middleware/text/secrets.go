@@ -0,0 +1,68 @@
+package textService
+
+import (
+	"log/slog"
+	"main_service/internal/lib/secrets"
+	"main_service/internal/models"
+)
+
+// * checkSecrets ищет секреты в тексте или файлах in. В режиме redact заменяет их в in,
+// * в режиме reject возвращает *secrets.FoundError. Режим берётся из in.SecretsMode,
+// * пустой — из конфига. Бинарное содержимое не проверяется.
+func (s *TextOperator) checkSecrets(in *models.PasteInput) error {
+	if s.secrets == nil {
+		return nil
+	}
+
+	mode := in.SecretsMode
+	if mode == "" {
+		mode = s.secretsMode
+	}
+
+	var found []secrets.Finding
+
+	switch {
+	case len(in.Files) > 0:
+		for i, f := range in.Files {
+			fs := s.secrets.Scan(f.Content)
+			if len(fs) == 0 {
+				continue
+			}
+
+			if mode == secrets.ModeRedact {
+				in.Files[i].Content = secrets.Redact(f.Content, fs)
+			}
+
+			for j := range fs {
+				fs[j].File = f.Name
+			}
+			found = append(found, fs...)
+		}
+	case models.IsText(in.ContentType):
+		found = s.secrets.Scan(in.Text)
+		if len(found) > 0 && mode == secrets.ModeRedact {
+			in.Text = secrets.Redact(in.Text, found)
+		}
+	}
+
+	if len(found) == 0 {
+		return nil
+	}
+
+	// Сами значения не логируем: лог не должен стать ещё одним местом утечки
+	for _, f := range found {
+		s.log.Warn("secret found",
+			slog.String("rule", f.Rule),
+			slog.String("file", f.File),
+			slog.Int("line", f.Line),
+			slog.Int64("owner_id", in.OwnerID),
+			slog.String("action", mode),
+		)
+	}
+
+	if mode == secrets.ModeRedact {
+		return nil
+	}
+
+	return &secrets.FoundError{Findings: found}
+}
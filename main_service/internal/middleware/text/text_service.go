@@ -3,15 +3,49 @@ package textService
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"main_service/internal/lib/language"
+	"main_service/internal/lib/lru"
+	"main_service/internal/lib/secrets"
 	"main_service/internal/models"
 	"main_service/internal/storage"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 type MySql interface {
-	SaveMetadata(ctx context.Context, hash string, ttlDays int) error
+	SaveMetadata(ctx context.Context, p *models.Paste, rev *models.Revision, files []models.File) error
 	GetByHash(ctx context.Context, hash string) (*models.Paste, error)
 	GetExpired(ctx context.Context) ([]string, error)
 	DeleteByHash(ctx context.Context, hash string) error
+	ListByOwner(ctx context.Context, ownerID int64, limit, offset int) ([]models.Paste, int, error)
+	UpdateExpiresAt(ctx context.Context, hash string, expiresAt time.Time) error
+	UpdateLanguage(ctx context.Context, hash, language string, confidence float64) error
+	ReserveRevision(ctx context.Context, hash string) (int, error)
+	SaveRevision(ctx context.Context, rev *models.Revision) error
+	Revision(ctx context.Context, hash string, rev int) (*models.Revision, error)
+	Revisions(ctx context.Context, hash string) ([]models.Revision, error)
+	Forks(ctx context.Context, hash string) ([]models.Paste, error)
+	ObjectRefs(ctx context.Context, key string) (int, error)
+	Files(ctx context.Context, hash string) ([]models.File, error)
+	File(ctx context.Context, hash, name string) (*models.File, error)
+	IndexText(ctx context.Context, hash, body string) error
+	CopyIndex(ctx context.Context, from, to string) error
+	Search(ctx context.Context, q string, ownerID int64, limit, offset int) ([]models.SearchHit, error)
+	AddReport(ctx context.Context, rep *models.Report) (int, error)
+	Reports(ctx context.Context, hash string) ([]models.Report, error)
+	ReportedPastes(ctx context.Context, limit, offset int) ([]models.ReportedPaste, int, error)
+	DeleteReports(ctx context.Context, hash string) error
+	SetModeration(ctx context.Context, hash, status, reason string) error
+	TakeDown(ctx context.Context, hash, reason string) error
+	ListPastes(ctx context.Context, f models.PasteFilter, limit, offset int) ([]models.Paste, int, error)
+	ExtendExpiresAt(ctx context.Context, hash string, days int) (time.Time, error)
+	PublicPastes(ctx context.Context, hashes []string) (map[string]models.Paste, error)
 }
 
 type Kafka interface {
@@ -19,99 +53,617 @@ type Kafka interface {
 }
 
 type MinIO interface {
-	SaveStringAsFile(ctx context.Context, hash, content string) error
-	GetString(ctx context.Context, hash string) (string, error)
-	DeleteFile(ctx context.Context, hash string) error
-	ListFiles(ctx context.Context) ([]string, error)
+	SaveStringAsFile(ctx context.Context, key, content string) error
+	SaveObject(ctx context.Context, key, content, contentType string) error
+	GetString(ctx context.Context, key string) (string, error)
+	DeleteFile(ctx context.Context, key string) error
+	ListFiles(ctx context.Context, modifiedBefore time.Time) ([]string, error)
 }
 
 type Redis interface {
-	Text(ctx context.Context, hash string) (string, error)
-	SaveText(ctx context.Context, hash, text string) error
+	Content(ctx context.Context, hash string) (*models.Content, error)
+	SaveContent(ctx context.Context, hash string, c models.Content, ttl time.Duration) error
+	View(ctx context.Context, hash, variant string) (string, time.Time, error)
+	SaveView(ctx context.Context, hash, variant, view string) error
 	DeleteText(ctx context.Context, hash string) error
+	Invalidations(ctx context.Context) <-chan string
 	IncPopularity(ctx context.Context, hash string) (int64, error)
+	AddViews(ctx context.Context, views map[string]int64) error
+	Delete(ctx context.Context, hash string) error
+	Trending(ctx context.Context, window string, limit int) ([]models.TrendingPaste, error)
+	Miss(ctx context.Context, hash string) (string, error)
+	SaveMiss(ctx context.Context, hash, reason string, ttl time.Duration) error
+	DeleteMiss(ctx context.Context, hash string) error
 }
 
+// * причины промахов в negative cache. Для истёкшего текста после ":" хранится время истечения, unix ms
+const (
+	missNotFound = "not_found"
+	missExpired  = "expired"
+)
+
 type TextOperator struct {
 	mysql               MySql
 	kafka               Kafka
 	minio               MinIO
 	redis               Redis
 	popularityThreshold int64
+	missTTL             time.Duration
+	cacheTTL            time.Duration
+	secrets             *secrets.Scanner
+	secretsMode         string
+	log                 *slog.Logger
+	autoHideReports     int
+
+	// * L1 кэш перед redis, nil — выключен
+	local    *lru.Cache[*models.Content]
+	localTTL time.Duration
+	viewsMu  sync.Mutex
+	views    map[string]int64 // * просмотры из кэша, ещё не отправленные в redis
+
+	base    context.Context // * контекст жизни сервера, от него отсчитываются общие загрузки
+	loadsMu sync.Mutex
+	loads   map[string]*flight // * одновременные загрузки одного текста из MySQL и MinIO
 }
 
-func New(mysql MySql, k Kafka, min MinIO, redis Redis, popularityThreshold int64) *TextOperator {
+// * missTTL — сколько помнить, что хэша нет или он истёк; 0 отключает negative cache
+func New(mysql MySql, k Kafka, min MinIO, redis Redis, popularityThreshold int64, missTTL time.Duration) *TextOperator {
 	return &TextOperator{
 		mysql:               mysql,
 		kafka:               k,
 		minio:               min,
 		redis:               redis,
 		popularityThreshold: popularityThreshold,
+		missTTL:             missTTL,
+		base:                context.Background(),
+		loads:               make(map[string]*flight),
 	}
 }
 
-func (s *TextOperator) SaveText(ctx context.Context, text string, ttl int) (string, error) {
+// * WithBaseContext задаёт контекст жизни сервера: с его отменой прерываются и загрузки, которые ещё ждут запросы
+func (s *TextOperator) WithBaseContext(ctx context.Context) *TextOperator {
+	s.base = ctx
+
+	return s
+}
+
+// * WithCacheTTL ограничивает время жизни текста в кэше redis; 0 — до истечения самого текста
+func (s *TextOperator) WithCacheTTL(ttl time.Duration) *TextOperator {
+	s.cacheTTL = ttl
+
+	return s
+}
+
+// * WithSecrets включает проверку сохраняемых текстов на секреты.
+// * mode — действие по умолчанию (secrets.ModeReject или secrets.ModeRedact), находки пишутся в log.
+func (s *TextOperator) WithSecrets(scanner *secrets.Scanner, mode string, log *slog.Logger) *TextOperator {
+	s.secrets = scanner
+	s.secretsMode = mode
+	s.log = log
+
+	return s
+}
+
+func (s *TextOperator) SaveText(ctx context.Context, in models.PasteInput) (string, error) {
+	// Проверяем до того, как взять хэш из очереди, чтобы отклонённый текст его не расходовал
+	if err := s.checkSecrets(&in); err != nil {
+		return "", err
+	}
+
 	hash, err := s.kafka.ReadMessage(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	err = s.minio.SaveStringAsFile(ctx, hash, text)
+	if len(in.Files) > 0 {
+		return hash, s.saveFiles(ctx, hash, in)
+	}
+
+	rev := &models.Revision{
+		Hash:        hash,
+		Rev:         1,
+		ObjectKey:   objectKey(hash, 1),
+		ContentType: models.ContentTypeText,
+		Size:        int64(len(in.Text)),
+		Digest:      models.Digest(in.Text),
+		AuthorID:    in.OwnerID,
+	}
+
+	if models.IsText(in.ContentType) {
+		err = s.minio.SaveStringAsFile(ctx, rev.ObjectKey, in.Text)
+	} else {
+		rev.ObjectKey = binaryKey(hash, 1)
+		rev.ContentType = in.ContentType
+		err = s.minio.SaveObject(ctx, rev.ObjectKey, in.Text, in.ContentType)
+	}
 	if err != nil {
 		return "", err
 	}
 
-	return hash, s.mysql.SaveMetadata(ctx, hash, ttl)
+	p := models.Paste{}
+	if !models.IsText(in.ContentType) {
+		return hash, s.publish(ctx, in, p, rev, nil)
+	}
+
+	p.Filename = in.Filename
+	p.Language, p.LanguageConfidence = detectLanguage(in.Language, in.Filename, in.Text)
+
+	if err := s.publish(ctx, in, p, rev, nil); err != nil {
+		return "", err
+	}
+
+	return hash, s.index(ctx, hash, in.Text)
+}
+
+// * detectLanguage возвращает язык, указанный автором, или определяет его по началу текста
+func detectLanguage(lang, filename, text string) (string, float64) {
+	if lang != "" {
+		return language.Normalize(lang), language.ConfidenceExplicit
+	}
+
+	return language.Detect(filename, text)
 }
 
-func (s *TextOperator) GetText(ctx context.Context, hash string) (string, error) {
-	if txt, _ := s.redis.Text(ctx, hash); txt != "" {
-		_, err := s.redis.IncPopularity(ctx, hash)
+// * publish сохраняет метаданные нового текста, чьи объекты уже лежат в MinIO.
+// * В p вызывающий код заполняет только ParentHash, язык и Filename, остальное берётся из in и rev.
+func (s *TextOperator) publish(ctx context.Context, in models.PasteInput, p models.Paste, rev *models.Revision, files []models.File) error {
+	// Хэш могли запросить до того, как он был выдан
+	if err := s.redis.DeleteMiss(ctx, rev.Hash); err != nil {
+		return err
+	}
+
+	visibility := in.Visibility
+	if visibility == "" {
+		visibility = models.VisibilityPublic
+	}
+
+	now := time.Now().UTC()
+	rev.CreatedAt = now
+
+	p.Hash = rev.Hash
+	p.OwnerID = in.OwnerID
+	p.Visibility = visibility
+	p.CurrentRev = rev.Rev
+	p.ObjectKey = rev.ObjectKey
+	p.ContentType = rev.ContentType
+	p.CreatedAt = now
+	p.ExpiresAt = now.AddDate(0, 0, in.TTLDays)
+
+	return s.mysql.SaveMetadata(ctx, &p, rev, files)
+}
+
+// * objectKey возвращает ключ объекта ревизии rev в MinIO
+func objectKey(hash string, rev int) string {
+	return fmt.Sprintf("%s/%d.txt", hash, rev)
+}
+
+// * binaryKey возвращает ключ объекта бинарной ревизии rev в MinIO
+func binaryKey(hash string, rev int) string {
+	return fmt.Sprintf("%s/%d.bin", hash, rev)
+}
+
+// * GetText возвращает ревизию rev текста (0 — текущую), если viewer имеет к нему доступ.
+// * Для бинарного содержимого возвращает storage.ErrBinaryContent.
+func (s *TextOperator) GetText(ctx context.Context, hash string, rev int, viewer models.Viewer) (string, error) {
+	c, err := s.GetContent(ctx, hash, rev, viewer)
+	if err != nil {
+		return "", err
+	}
+
+	if !models.IsText(c.ContentType) {
+		return "", storage.ErrBinaryContent
+	}
+
+	return c.Data, nil
+}
+
+// * GetContent возвращает содержимое ревизии rev (0 — текущей) вместе с MIME типом.
+// * Приватные тексты никогда не попадают в redis, поэтому попадание в кэш не требует проверки доступа.
+// * В redis лежит только текущая ревизия. Чужой приватный текст неотличим от несуществующего.
+func (s *TextOperator) GetContent(ctx context.Context, hash string, rev int, viewer models.Viewer) (*models.Content, error) {
+	if rev == 0 {
+		if c := s.cachedContent(ctx, hash); c != nil {
+			if !c.ExpiresAt.After(time.Now()) {
+				return nil, s.expired(ctx, hash, c.ExpiresAt)
+			}
+
+			if err := s.countView(ctx, hash); err != nil {
+				return nil, err
+			}
+
+			return c, nil
+		}
+	}
+
+	if err := s.missed(ctx, hash); err != nil {
+		return nil, err
+	}
+
+	res, err := s.load(ctx, hash, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	// Доступ проверяется раньше ошибок загрузки ревизии, чтобы они не выдавали чужой приватный текст
+	if err := readable(res.paste, viewer); err != nil {
+		return nil, err
+	}
+
+	if res.err != nil {
+		return nil, res.err
+	}
+
+	if !res.leader {
+		if err := s.countView(ctx, hash); err != nil {
+			return nil, err
+		}
+	}
+
+	return res.content, nil
+}
+
+// * ContentInfo возвращает то же, что GetContent, но без содержимого: чтобы ответить на условный запрос,
+// * не читая объект из MinIO. Просмотр не учитывается. Пустой Digest — у ревизии он не сохранён.
+func (s *TextOperator) ContentInfo(ctx context.Context, hash string, rev int, viewer models.Viewer) (*models.Content, error) {
+	if rev == 0 {
+		if c := s.cachedContent(ctx, hash); c != nil {
+			if !c.ExpiresAt.After(time.Now()) {
+				return nil, s.expired(ctx, hash, c.ExpiresAt)
+			}
+
+			return c, nil
+		}
+	}
+
+	if err := s.missed(ctx, hash); err != nil {
+		return nil, err
+	}
+
+	paste, err := s.readablePaste(ctx, hash, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &models.Content{
+		ContentType: paste.ContentType,
+		Language:    paste.Language,
+		Digest:      paste.Digest,
+		Size:        paste.Size,
+		Visibility:  paste.Visibility,
+		Mutable:     paste.OwnerID != 0,
+		CreatedAt:   paste.UpdatedAt,
+		ExpiresAt:   paste.ExpiresAt,
+	}
+
+	if rev != 0 && rev != paste.CurrentRev {
+		r, err := s.mysql.Revision(ctx, hash, rev)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
-		return txt, nil
+		c.ContentType, c.Digest, c.Size, c.CreatedAt = r.ContentType, r.Digest, r.Size, r.CreatedAt
+		c.Mutable = paste.OwnerID != 0 && r.ObjectKey == paste.ObjectKey
+	}
+
+	return c, nil
+}
+
+// * missed возвращает ошибку, если hash лежит в negative cache
+func (s *TextOperator) missed(ctx context.Context, hash string) error {
+	if s.missTTL == 0 {
+		return nil
 	}
 
-	_, err := s.mysql.GetByHash(ctx, hash)
+	reason, _ := s.redis.Miss(ctx, hash)
+	if reason == missNotFound {
+		return storage.ErrTextNotFound
+	}
+	if at, ok := strings.CutPrefix(reason, missExpired+":"); ok {
+		if ms, err := strconv.ParseInt(at, 10, 64); err == nil {
+			return &storage.ExpiredError{ExpiresAt: time.UnixMilli(ms).UTC()}
+		}
+	}
+
+	return nil
+}
+
+// * loaded — результат загрузки текста из MySQL и MinIO, общий для всех ждавших её запросов
+type loaded struct {
+	paste   *models.Paste
+	content *models.Content
+	err     error // * ошибка после получения метаданных: её можно отдавать только тем, кто может читать текст
+	leader  bool  // * этот запрос сам выполнил загрузку и уже учёл просмотр
+}
+
+// * flight — загрузка, которую ждут один или несколько запросов
+type flight struct {
+	done    chan struct{}
+	res     loaded
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// * load загружает ревизию rev текста hash из MySQL и MinIO. Одновременные загрузки одной ревизии
+// * объединяются: в хранилища идёт один запрос, просмотр учитывается и текст попадает в redis один раз.
+// * Загрузка живёт, пока её ждёт хоть один запрос: уход последнего её прерывает. Дедлайн берётся у первого.
+func (s *TextOperator) load(ctx context.Context, hash string, rev int) (loaded, error) {
+	key := hash + "/" + strconv.Itoa(rev)
+
+	s.loadsMu.Lock()
+	f, ok := s.loads[key]
+	leader := !ok
+	if leader {
+		// Спан первого запроса остаётся родителем, чтобы запросы к хранилищам попали в его трейс
+		fctx, cancel := context.WithCancel(trace.ContextWithSpanContext(s.base, trace.SpanContextFromContext(ctx)))
+		if deadline, ok := ctx.Deadline(); ok {
+			fctx, cancel = context.WithDeadline(fctx, deadline)
+		}
+
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		s.loads[key] = f
+
+		go func() {
+			f.res, f.err = s.fetch(fctx, hash, rev)
+			cancel()
+
+			s.forget(key, f)
+			close(f.done)
+		}()
+	}
+	f.waiters++
+	s.loadsMu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		s.loadsMu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			f.cancel()
+			if s.loads[key] == f {
+				delete(s.loads, key)
+			}
+		}
+		s.loadsMu.Unlock()
+
+		return loaded{}, ctx.Err()
+	case <-f.done:
+		if f.err != nil {
+			return loaded{}, f.err
+		}
+
+		res := f.res
+		res.leader = leader
+
+		return res, nil
+	}
+}
+
+// * forget убирает завершённую загрузку f, чтобы следующий запрос начал новую
+func (s *TextOperator) forget(key string, f *flight) {
+	s.loadsMu.Lock()
+	defer s.loadsMu.Unlock()
+
+	if s.loads[key] == f {
+		delete(s.loads, key)
+	}
+}
+
+// * fetch читает метаданные и содержимое ревизии rev, учитывает просмотр и кладёт популярный текст в redis.
+// * Содержимое скрытых модератором текстов не читается: его всё равно нельзя отдать.
+func (s *TextOperator) fetch(ctx context.Context, hash string, rev int) (loaded, error) {
+	paste, err := s.mysql.GetByHash(ctx, hash)
 	if err != nil {
 		if errors.Is(err, storage.ErrTextNotFound) {
-			return "", storage.ErrTextNotFound
+			s.rememberMiss(ctx, hash, missNotFound)
+			return loaded{}, storage.ErrTextNotFound
 		}
 
-		if errors.Is(err, storage.ErrTTLIsExpired) {
-			return "", storage.ErrTTLIsExpired
+		var expired *storage.ExpiredError
+		if !errors.As(err, &expired) {
+			return loaded{}, err
 		}
 
-		return "", err
+		if paste == nil {
+			return loaded{}, s.expired(ctx, hash, expired.ExpiresAt)
+		}
+
+		// Истечение, как и остальные ошибки после метаданных, видят только те, кто может читать текст.
+		// Ответы negative cache отдаются без проверки доступа, поэтому приватный текст туда не попадает.
+		if paste.Visibility == models.VisibilityPrivate {
+			return loaded{paste: paste, err: expired}, nil
+		}
+
+		return loaded{paste: paste, err: s.expired(ctx, hash, expired.ExpiresAt)}, nil
+	}
+
+	if paste.Moderation != "" {
+		return loaded{paste: paste}, nil
 	}
 
-	text, err := s.minio.GetString(ctx, hash)
+	key, contentType, createdAt := paste.ObjectKey, paste.ContentType, paste.UpdatedAt
+	if rev != 0 && rev != paste.CurrentRev {
+		r, err := s.mysql.Revision(ctx, hash, rev)
+		if err != nil {
+			return loaded{paste: paste, err: err}, nil
+		}
+		key, contentType, createdAt = r.ObjectKey, r.ContentType, r.CreatedAt
+	}
+
+	data, err := s.minio.GetString(ctx, key)
 	if err != nil {
-		return "", err
+		return loaded{}, err
+	}
+
+	c := &models.Content{
+		Data:        data,
+		ContentType: contentType,
+		Language:    paste.Language,
+		Digest:      models.Digest(data),
+		Size:        int64(len(data)),
+		Visibility:  paste.Visibility,
+		// Старые ревизии не меняются, текущую владелец может заменить
+		Mutable:   paste.OwnerID != 0 && key == paste.ObjectKey,
+		CreatedAt: createdAt,
+		ExpiresAt: paste.ExpiresAt,
 	}
 
 	views, err := s.redis.IncPopularity(ctx, hash)
 	if err != nil {
-		return text, err
+		return loaded{}, err
+	}
+
+	if views >= s.popularityThreshold && paste.Visibility != models.VisibilityPrivate && key == paste.ObjectKey {
+		if ttl := s.contentTTL(paste); ttl > 0 {
+			_ = s.redis.SaveContent(ctx, hash, *c, ttl)
+		}
+	}
+
+	return loaded{paste: paste, content: c}, nil
+}
+
+// * contentTTL возвращает, сколько держать текст p в кэше: до его истечения, но не дольше cacheTTL
+func (s *TextOperator) contentTTL(p *models.Paste) time.Duration {
+	ttl := time.Until(p.ExpiresAt)
+	if s.cacheTTL > 0 && ttl > s.cacheTTL {
+		ttl = s.cacheTTL
 	}
 
-	if views >= s.popularityThreshold {
-		_ = s.redis.SaveText(ctx, hash, text)
+	return ttl
+}
+
+// * rememberMiss кладёт промах в negative cache. Ошибка redis не должна ломать ответ 404.
+func (s *TextOperator) rememberMiss(ctx context.Context, hash, reason string) {
+	if s.missTTL > 0 {
+		_ = s.redis.SaveMiss(ctx, hash, reason, s.missTTL)
 	}
+}
+
+// * expired возвращает ошибку истёкшего в at текста, убирая его из кэша и запоминая в negative cache.
+// * Данные в хранилищах удалит ближайшая очистка.
+func (s *TextOperator) expired(ctx context.Context, hash string, at time.Time) error {
+	_ = s.dropCache(ctx, hash)
+	s.rememberMiss(ctx, hash, missExpired+":"+strconv.FormatInt(at.UnixMilli(), 10))
 
-	return text, nil
+	return &storage.ExpiredError{ExpiresAt: at}
 }
 
+// * DeleteText удаляет текст со всеми ревизиями и файлами из redis (кэш и рейтинг популярности), MySQL и MinIO.
+// * Объекты, на которые ещё ссылаются форки, в MinIO остаются.
 func (s *TextOperator) DeleteText(ctx context.Context, hash string) error {
-	if err := s.mysql.DeleteByHash(ctx, hash); err != nil {
+	keys, err := s.objectKeys(ctx, hash)
+	if err != nil {
+		return err
+	}
+
+	if err := s.dropCache(ctx, hash); err != nil {
+		return err
+	}
+
+	if err := s.redis.Delete(ctx, hash); err != nil {
 		return err
 	}
 
-	if err := s.minio.DeleteFile(ctx, hash); err != nil {
+	if err := s.mysql.DeleteByHash(ctx, hash); err != nil {
 		return err
 	}
 
+	return s.deleteUnreferenced(ctx, keys)
+}
+
+// * objectKeys возвращает ключи всех объектов текста в MinIO: ревизий и файлов
+func (s *TextOperator) objectKeys(ctx context.Context, hash string) ([]string, error) {
+	revs, err := s.mysql.Revisions(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := s.mysql.Files(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(revs)+len(files))
+	for _, rev := range revs {
+		keys = append(keys, rev.ObjectKey)
+	}
+	for _, f := range files {
+		// Первая ревизия многофайлового текста ссылается на объект первого файла
+		if len(revs) == 0 || f.ObjectKey != revs[0].ObjectKey {
+			keys = append(keys, f.ObjectKey)
+		}
+	}
+
+	return keys, nil
+}
+
+// * deleteUnreferenced удаляет из MinIO объекты, на которые больше не ссылается ни одна ревизия или файл
+func (s *TextOperator) deleteUnreferenced(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		refs, err := s.mysql.ObjectRefs(ctx, key)
+		if err != nil {
+			return err
+		}
+		if refs > 0 {
+			continue
+		}
+
+		if err := s.minio.DeleteFile(ctx, key); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+// * OwnMetadata возвращает метаданные текста, если он принадлежит ownerID
+func (s *TextOperator) OwnMetadata(ctx context.Context, hash string, ownerID int64) (*models.Paste, error) {
+	return s.ownPaste(ctx, hash, ownerID)
+}
+
+// * DeleteOwnText удаляет текст, если он принадлежит ownerID
+func (s *TextOperator) DeleteOwnText(ctx context.Context, hash string, ownerID int64) error {
+	if _, err := s.ownPaste(ctx, hash, ownerID); err != nil {
+		return err
+	}
+
+	return s.DeleteText(ctx, hash)
+}
+
+// * ExtendOwnText продлевает жизнь текста ownerID на days дней и возвращает новое время истечения
+func (s *TextOperator) ExtendOwnText(ctx context.Context, hash string, ownerID int64, days int) (time.Time, error) {
+	p, err := s.ownPaste(ctx, hash, ownerID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	expiresAt := p.ExpiresAt.AddDate(0, 0, days)
+	if err := s.mysql.UpdateExpiresAt(ctx, hash, expiresAt); err != nil {
+		return time.Time{}, err
+	}
+
+	// Иначе кэш продолжит отдавать старое время истечения
+	if err := s.dropCache(ctx, hash); err != nil {
+		return time.Time{}, err
+	}
+
+	return expiresAt, nil
+}
+
+// * ListByOwner возвращает страницу текстов пользователя и их общее количество
+func (s *TextOperator) ListByOwner(ctx context.Context, ownerID int64, limit, offset int) ([]models.Paste, int, error) {
+	return s.mysql.ListByOwner(ctx, ownerID, limit, offset)
+}
+
+func (s *TextOperator) ownPaste(ctx context.Context, hash string, ownerID int64) (*models.Paste, error) {
+	p, err := s.mysql.GetByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.OwnerID == 0 || p.OwnerID != ownerID {
+		return nil, storage.ErrNotOwner
+	}
+
+	return p, nil
+}
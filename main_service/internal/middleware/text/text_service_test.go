@@ -0,0 +1,738 @@
+package textService
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// * calls считает вызовы методов фейка
+type calls struct {
+	mu sync.Mutex
+	n  map[string]int
+}
+
+func (c *calls) add(method string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.n == nil {
+		c.n = make(map[string]int)
+	}
+	c.n[method]++
+}
+
+func (c *calls) count(method string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.n[method]
+}
+
+// * fakeMySql хранит метаданные в памяти. Методы, которые тесту не нужны, паникуют.
+type fakeMySql struct {
+	MySql
+	calls
+
+	mu     sync.Mutex
+	pastes map[string]models.Paste
+	revs   map[string][]models.Revision
+	files  map[string][]models.File
+
+	// * если не nil, GetByHash ждёт его закрытия или отмены ctx
+	gate chan struct{}
+}
+
+func newFakeMySql() *fakeMySql {
+	return &fakeMySql{
+		pastes: make(map[string]models.Paste),
+		revs:   make(map[string][]models.Revision),
+		files:  make(map[string][]models.File),
+	}
+}
+
+func (m *fakeMySql) GetByHash(ctx context.Context, hash string) (*models.Paste, error) {
+	m.add("GetByHash")
+
+	if m.gate != nil {
+		select {
+		case <-m.gate:
+		case <-ctx.Done():
+			m.add("GetByHash aborted")
+			return nil, ctx.Err()
+		}
+	}
+
+	m.mu.Lock()
+	p, ok := m.pastes[hash]
+	m.mu.Unlock()
+
+	if !ok {
+		return nil, storage.ErrTextNotFound
+	}
+
+	if p.ExpiresAt.Before(time.Now()) {
+		return &p, &storage.ExpiredError{ExpiresAt: p.ExpiresAt}
+	}
+
+	return &p, nil
+}
+
+func (m *fakeMySql) SaveMetadata(ctx context.Context, p *models.Paste, rev *models.Revision, files []models.File) error {
+	m.add("SaveMetadata")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pastes[p.Hash] = *p
+	m.revs[p.Hash] = append(m.revs[p.Hash], *rev)
+	if len(files) > 0 {
+		m.files[p.Hash] = files
+	}
+
+	return nil
+}
+
+func (m *fakeMySql) Revision(ctx context.Context, hash string, rev int) (*models.Revision, error) {
+	m.add("Revision")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, r := range m.revs[hash] {
+		if r.Rev == rev {
+			return &r, nil
+		}
+	}
+
+	return nil, storage.ErrRevNotFound
+}
+
+func (m *fakeMySql) ReserveRevision(ctx context.Context, hash string) (int, error) {
+	m.add("ReserveRevision")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.revs[hash]) + 1, nil
+}
+
+func (m *fakeMySql) SaveRevision(ctx context.Context, rev *models.Revision) error {
+	m.add("SaveRevision")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.revs[rev.Hash] = append(m.revs[rev.Hash], *rev)
+
+	p := m.pastes[rev.Hash]
+	p.CurrentRev = rev.Rev
+	p.ObjectKey = rev.ObjectKey
+	p.Size = rev.Size
+	p.Digest = rev.Digest
+	m.pastes[rev.Hash] = p
+
+	return nil
+}
+
+func (m *fakeMySql) UpdateLanguage(ctx context.Context, hash, language string, confidence float64) error {
+	m.add("UpdateLanguage")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p := m.pastes[hash]
+	p.Language = language
+	p.LanguageConfidence = confidence
+	m.pastes[hash] = p
+
+	return nil
+}
+
+func (m *fakeMySql) Files(ctx context.Context, hash string) ([]models.File, error) {
+	m.add("Files")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.files[hash], nil
+}
+
+func (m *fakeMySql) IndexText(ctx context.Context, hash, body string) error {
+	m.add("IndexText")
+
+	return nil
+}
+
+func (m *fakeMySql) CopyIndex(ctx context.Context, from, to string) error {
+	m.add("CopyIndex")
+
+	return nil
+}
+
+// * fakeMinIO хранит объекты в памяти
+type fakeMinIO struct {
+	MinIO
+	calls
+
+	mu      sync.Mutex
+	objects map[string]string
+
+	// * если не nil, чтение и запись объектов ждут его закрытия или отмены ctx
+	gate chan struct{}
+}
+
+func newFakeMinIO() *fakeMinIO {
+	return &fakeMinIO{objects: make(map[string]string)}
+}
+
+// * wait ждёт gate, как медленный MinIO. Отмену ctx запоминает как "<method> aborted".
+func (m *fakeMinIO) wait(ctx context.Context, method string) error {
+	if m.gate == nil {
+		return nil
+	}
+
+	select {
+	case <-m.gate:
+		return nil
+	case <-ctx.Done():
+		m.add(method + " aborted")
+		return ctx.Err()
+	}
+}
+
+func (m *fakeMinIO) GetString(ctx context.Context, key string) (string, error) {
+	m.add("GetString")
+
+	if err := m.wait(ctx, "GetString"); err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, ok := m.objects[key]
+	if !ok {
+		return "", errors.New("no such object " + key)
+	}
+
+	return data, nil
+}
+
+func (m *fakeMinIO) SaveStringAsFile(ctx context.Context, key, content string) error {
+	m.add("SaveStringAsFile")
+
+	if err := m.wait(ctx, "SaveStringAsFile"); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.objects[key] = content
+
+	return nil
+}
+
+// * fakeRedis хранит кэш, negative cache и счётчики просмотров в памяти
+type fakeRedis struct {
+	Redis
+	calls
+
+	mu       sync.Mutex
+	content  map[string]models.Content
+	misses   map[string]string
+	views    map[string]int64
+	addViews map[string]int64 // * просмотры, пришедшие через AddViews
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{
+		content:  make(map[string]models.Content),
+		misses:   make(map[string]string),
+		views:    make(map[string]int64),
+		addViews: make(map[string]int64),
+	}
+}
+
+func (r *fakeRedis) Content(ctx context.Context, hash string) (*models.Content, error) {
+	r.add("Content")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.content[hash]
+	if !ok {
+		return nil, nil
+	}
+
+	return &c, nil
+}
+
+func (r *fakeRedis) SaveContent(ctx context.Context, hash string, c models.Content, ttl time.Duration) error {
+	r.add("SaveContent")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.content[hash] = c
+
+	return nil
+}
+
+func (r *fakeRedis) DeleteText(ctx context.Context, hash string) error {
+	r.add("DeleteText")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.content, hash)
+
+	return nil
+}
+
+func (r *fakeRedis) IncPopularity(ctx context.Context, hash string) (int64, error) {
+	r.add("IncPopularity")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.views[hash]++
+
+	return r.views[hash], nil
+}
+
+func (r *fakeRedis) AddViews(ctx context.Context, views map[string]int64) error {
+	r.add("AddViews")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for hash, n := range views {
+		r.addViews[hash] += n
+	}
+
+	return nil
+}
+
+func (r *fakeRedis) Miss(ctx context.Context, hash string) (string, error) {
+	r.add("Miss")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.misses[hash], nil
+}
+
+func (r *fakeRedis) SaveMiss(ctx context.Context, hash, reason string, ttl time.Duration) error {
+	r.add("SaveMiss")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.misses[hash] = reason
+
+	return nil
+}
+
+func (r *fakeRedis) DeleteMiss(ctx context.Context, hash string) error {
+	r.add("DeleteMiss")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.misses, hash)
+
+	return nil
+}
+
+// * fakeKafka выдаёт хэши hash1, hash2, ...
+type fakeKafka struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (k *fakeKafka) ReadMessage(ctx context.Context) (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.n++
+
+	return "hash" + strconv.Itoa(k.n), nil
+}
+
+type fakes struct {
+	mysql *fakeMySql
+	minio *fakeMinIO
+	redis *fakeRedis
+}
+
+// * newTestService создаёт сервис поверх фейков: текст попадает в кэш с первого просмотра,
+// * промахи помнятся минуту
+func newTestService() (*TextOperator, fakes) {
+	f := fakes{mysql: newFakeMySql(), minio: newFakeMinIO(), redis: newFakeRedis()}
+
+	return New(f.mysql, &fakeKafka{}, f.minio, f.redis, 1, time.Minute), f
+}
+
+// * addPaste кладёт в фейки текст из одной ревизии
+func (f fakes) addPaste(p models.Paste, data string) {
+	p.CurrentRev = 1
+	p.ObjectKey = objectKey(p.Hash, 1)
+	p.ContentType = models.ContentTypeText
+	p.Digest = models.Digest(data)
+	p.Size = int64(len(data))
+
+	f.mysql.pastes[p.Hash] = p
+	f.mysql.revs[p.Hash] = []models.Revision{{Hash: p.Hash, Rev: 1, ObjectKey: p.ObjectKey, ContentType: p.ContentType, Digest: p.Digest, Size: p.Size}}
+	f.minio.objects[p.ObjectKey] = data
+}
+
+func TestGetContentCoalescesConcurrentLoads(t *testing.T) {
+	const readers = 50
+
+	s, f := newTestService()
+	s.WithLocalCache(1<<20, time.Minute, slog.New(slog.DiscardHandler))
+
+	f.addPaste(models.Paste{Hash: "abc", Visibility: models.VisibilityPublic, ExpiresAt: time.Now().Add(time.Hour)}, "hello")
+	f.mysql.gate = make(chan struct{})
+
+	var wg sync.WaitGroup
+	results := make([]*models.Content, readers)
+	errs := make([]error, readers)
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = s.GetContent(context.Background(), "abc", 0, models.Viewer{})
+		}()
+	}
+
+	// Все читатели прошли кэши и negative cache; даём им встать в очередь за загрузкой
+	for f.redis.count("Miss") < readers {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(f.mysql.gate)
+	wg.Wait()
+
+	for i := 0; i < readers; i++ {
+		if errs[i] != nil || results[i] == nil || results[i].Data != "hello" {
+			t.Fatalf("GetContent() #%d = %v, %v", i, results[i], errs[i])
+		}
+	}
+
+	for _, tt := range []struct {
+		calls  *calls
+		method string
+	}{
+		{&f.mysql.calls, "GetByHash"},
+		{&f.minio.calls, "GetString"},
+		{&f.redis.calls, "IncPopularity"},
+		{&f.redis.calls, "SaveContent"},
+	} {
+		if n := tt.calls.count(tt.method); n != 1 {
+			t.Errorf("%s called %d times, want 1", tt.method, n)
+		}
+	}
+
+	// Просмотры остальных читателей копятся в L1 и уходят в redis одной пачкой
+	s.FlushViews(context.Background())
+	if got := f.redis.addViews["abc"]; got != readers-1 {
+		t.Errorf("flushed %d views, want %d", got, readers-1)
+	}
+}
+
+func TestGetContentDeadline(t *testing.T) {
+	s, f := newTestService()
+	f.mysql.gate = make(chan struct{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := s.GetContent(ctx, "abc", 0, models.Viewer{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetContent() error = %v, want DeadlineExceeded", err)
+	}
+
+	// Загрузка получает дедлайн запроса и прерывает запрос к MySQL
+	deadline := time.Now().Add(5 * time.Second)
+	for f.mysql.count("GetByHash aborted") == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("MySQL call was not aborted by the deadline")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGetContentCanceled(t *testing.T) {
+	s, f := newTestService()
+	f.mysql.gate = make(chan struct{})
+	defer close(f.mysql.gate)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		for f.mysql.count("GetByHash") == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+
+	if _, err := s.GetContent(ctx, "abc", 0, models.Viewer{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetContent() error = %v, want Canceled", err)
+	}
+
+	// Других ждущих нет, поэтому загрузка прерывает запрос к MySQL
+	deadline := time.Now().Add(5 * time.Second)
+	for f.mysql.count("GetByHash aborted") == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("MySQL call was not aborted after the only reader left")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGetContentCanceledWaiterKeepsSharedLoad(t *testing.T) {
+	s, f := newTestService()
+
+	f.addPaste(models.Paste{Hash: "abc", Visibility: models.VisibilityPublic, ExpiresAt: time.Now().Add(time.Hour)}, "hello")
+	f.mysql.gate = make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error)
+	go func() {
+		_, err := s.GetContent(ctx, "abc", 0, models.Viewer{})
+		canceled <- err
+	}()
+
+	for f.mysql.count("GetByHash") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	stayed := make(chan error)
+	go func() {
+		c, err := s.GetContent(context.Background(), "abc", 0, models.Viewer{})
+		if err == nil && c.Data != "hello" {
+			err = errors.New("unexpected content " + c.Data)
+		}
+		stayed <- err
+	}()
+
+	// Второй читатель встал в очередь за той же загрузкой
+	for f.redis.count("Miss") < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-canceled; !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetContent() error = %v, want Canceled", err)
+	}
+
+	close(f.mysql.gate)
+	if err := <-stayed; err != nil {
+		t.Fatalf("GetContent() for the remaining reader error = %v", err)
+	}
+
+	if n := f.mysql.count("GetByHash aborted"); n != 0 {
+		t.Errorf("shared load aborted %d times while a reader was still waiting", n)
+	}
+	if n := f.mysql.count("GetByHash"); n != 1 {
+		t.Errorf("GetByHash called %d times, want 1", n)
+	}
+}
+
+func TestGetContentExpiredCacheHit(t *testing.T) {
+	s, f := newTestService()
+
+	expiresAt := time.Now().Add(-time.Minute).UTC().Truncate(time.Millisecond)
+	f.redis.content["abc"] = models.Content{Data: "hello", Visibility: models.VisibilityPublic, ExpiresAt: expiresAt}
+
+	_, err := s.GetContent(context.Background(), "abc", 0, models.Viewer{})
+
+	var expired *storage.ExpiredError
+	if !errors.As(err, &expired) || !expired.ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("GetContent() error = %v, want ExpiredError at %v", err, expiresAt)
+	}
+
+	if _, ok := f.redis.content["abc"]; ok {
+		t.Error("expired text is still cached")
+	}
+	if got, want := f.redis.misses["abc"], missExpired+":"+strconv.FormatInt(expiresAt.UnixMilli(), 10); got != want {
+		t.Errorf("miss = %q, want %q", got, want)
+	}
+	if n := f.mysql.count("GetByHash"); n != 0 {
+		t.Errorf("GetByHash called %d times on cache hit", n)
+	}
+	if n := f.redis.count("IncPopularity"); n != 0 {
+		t.Errorf("expired text counted %d views", n)
+	}
+}
+
+func TestGetContentExpiredCacheMiss(t *testing.T) {
+	s, f := newTestService()
+
+	expiresAt := time.Now().Add(-time.Minute).UTC().Truncate(time.Millisecond)
+	f.addPaste(models.Paste{Hash: "abc", Visibility: models.VisibilityUnlisted, ExpiresAt: expiresAt}, "hello")
+
+	for i := 0; i < 2; i++ {
+		_, err := s.GetContent(context.Background(), "abc", 0, models.Viewer{})
+
+		var expired *storage.ExpiredError
+		if !errors.As(err, &expired) || !expired.ExpiresAt.Equal(expiresAt) {
+			t.Fatalf("GetContent() #%d error = %v, want ExpiredError at %v", i, err, expiresAt)
+		}
+	}
+
+	// Второй запрос обслужил negative cache
+	if n := f.mysql.count("GetByHash"); n != 1 {
+		t.Errorf("GetByHash called %d times, want 1", n)
+	}
+	if n := f.minio.count("GetString"); n != 0 {
+		t.Errorf("GetString called %d times for expired text", n)
+	}
+}
+
+func TestGetContentExpiredPrivate(t *testing.T) {
+	s, f := newTestService()
+
+	expiresAt := time.Now().Add(-time.Minute).UTC().Truncate(time.Millisecond)
+	f.addPaste(models.Paste{Hash: "abc", OwnerID: 7, Visibility: models.VisibilityPrivate, ExpiresAt: expiresAt}, "hello")
+
+	tests := []struct {
+		name    string
+		viewer  models.Viewer
+		expired bool
+	}{
+		{name: "anonymous", viewer: models.Viewer{}},
+		{name: "other user", viewer: models.Viewer{UserID: 8}},
+		{name: "owner", viewer: models.Viewer{UserID: 7}, expired: true},
+		{name: "signed link", viewer: models.Viewer{Signed: true}, expired: true},
+		// Ответ владельцу не должен попасть в negative cache и открыться остальным
+		{name: "anonymous after owner", viewer: models.Viewer{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.GetContent(context.Background(), "abc", 0, tt.viewer)
+
+			var expired *storage.ExpiredError
+			if tt.expired && !errors.As(err, &expired) {
+				t.Errorf("GetContent() error = %v, want ExpiredError", err)
+			}
+			if !tt.expired && !errors.Is(err, storage.ErrTextNotFound) {
+				t.Errorf("GetContent() error = %v, want ErrTextNotFound", err)
+			}
+		})
+	}
+
+	if n := f.redis.count("SaveMiss"); n != 0 {
+		t.Errorf("private text remembered in negative cache %d times", n)
+	}
+}
+
+func TestReadablePasteExpiredPrivate(t *testing.T) {
+	s, f := newTestService()
+
+	f.addPaste(models.Paste{Hash: "abc", OwnerID: 7, Visibility: models.VisibilityPrivate, ExpiresAt: time.Now().Add(-time.Minute)}, "hello")
+
+	if _, err := s.Revisions(context.Background(), "abc", models.Viewer{UserID: 8}); !errors.Is(err, storage.ErrTextNotFound) {
+		t.Errorf("Revisions() for stranger error = %v, want ErrTextNotFound", err)
+	}
+
+	if _, err := s.Revisions(context.Background(), "abc", models.Viewer{UserID: 7}); !errors.Is(err, storage.ErrTTLIsExpired) {
+		t.Errorf("Revisions() for owner error = %v, want ErrTTLIsExpired", err)
+	}
+}
+
+func TestContentInfo(t *testing.T) {
+	s, f := newTestService()
+
+	f.addPaste(models.Paste{Hash: "pub", Visibility: models.VisibilityPublic, ExpiresAt: time.Now().Add(time.Hour)}, "hello")
+	f.addPaste(models.Paste{Hash: "priv", OwnerID: 7, Visibility: models.VisibilityPrivate, ExpiresAt: time.Now().Add(time.Hour)}, "secret")
+
+	c, err := s.ContentInfo(context.Background(), "pub", 0, models.Viewer{})
+	if err != nil || c.Digest != models.Digest("hello") || c.Size != 5 || c.Data != "" {
+		t.Fatalf("ContentInfo() = %+v, %v", c, err)
+	}
+
+	if _, err := s.ContentInfo(context.Background(), "priv", 0, models.Viewer{UserID: 8}); !errors.Is(err, storage.ErrTextNotFound) {
+		t.Errorf("ContentInfo() for stranger error = %v, want ErrTextNotFound", err)
+	}
+
+	if c, err := s.ContentInfo(context.Background(), "priv", 1, models.Viewer{UserID: 7}); err != nil || c.Digest != models.Digest("secret") {
+		t.Errorf("ContentInfo() for owner = %+v, %v", c, err)
+	}
+
+	// Проверка условного запроса не читает содержимое и не считается просмотром
+	if n := f.minio.count("GetString"); n != 0 {
+		t.Errorf("GetString called %d times", n)
+	}
+	if n := f.redis.count("IncPopularity"); n != 0 {
+		t.Errorf("counted %d views", n)
+	}
+}
+
+// * cancelOn отменяет ctx, как только c насчитает вызов method
+func cancelOn(c *calls, method string) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		for c.count(method) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+
+	return ctx, cancel
+}
+
+func TestGetContentCanceledDownload(t *testing.T) {
+	s, f := newTestService()
+
+	f.addPaste(models.Paste{Hash: "abc", Visibility: models.VisibilityPublic, ExpiresAt: time.Now().Add(time.Hour)}, "hello")
+	f.minio.gate = make(chan struct{})
+	defer close(f.minio.gate)
+
+	ctx, cancel := cancelOn(&f.minio.calls, "GetString")
+	defer cancel()
+
+	if _, err := s.GetContent(ctx, "abc", 0, models.Viewer{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetContent() error = %v, want Canceled", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for f.minio.count("GetString aborted") == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("MinIO download was not aborted after the reader left")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSaveTextCanceled(t *testing.T) {
+	s, f := newTestService()
+
+	f.minio.gate = make(chan struct{})
+	defer close(f.minio.gate)
+
+	ctx, cancel := cancelOn(&f.minio.calls, "SaveStringAsFile")
+	defer cancel()
+
+	_, err := s.SaveText(ctx, models.PasteInput{Text: "hello", TTLDays: 1})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("SaveText() error = %v, want Canceled", err)
+	}
+
+	// Загрузку прервала отмена запроса, метаданные без объекта не сохраняются
+	if n := f.minio.count("SaveStringAsFile aborted"); n != 1 {
+		t.Errorf("MinIO upload aborted %d times, want 1", n)
+	}
+	if n := f.mysql.count("SaveMetadata"); n != 0 {
+		t.Errorf("SaveMetadata called %d times after cancel", n)
+	}
+}
@@ -0,0 +1,46 @@
+package textService
+
+import (
+	"context"
+	"main_service/internal/models"
+)
+
+// * во сколько раз больше кандидатов берём из redis, чтобы после отсева
+// * приватных и истёкших текстов осталось limit
+const trendingOverfetch = 3
+
+// * Trending возвращает до limit публичных неистёкших текстов, которые больше всего смотрели за окно window
+func (s *TextOperator) Trending(ctx context.Context, window string, limit int) ([]models.TrendingPaste, error) {
+	candidates, err := s.redis.Trending(ctx, window, limit*trendingOverfetch)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		hashes = append(hashes, c.Hash)
+	}
+
+	public, err := s.mysql.PublicPastes(ctx, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	trending := make([]models.TrendingPaste, 0, limit)
+	for _, c := range candidates {
+		p, ok := public[c.Hash]
+		if !ok {
+			continue
+		}
+
+		c.Language = p.Language
+		c.CreatedAt = p.CreatedAt
+		trending = append(trending, c)
+
+		if len(trending) == limit {
+			break
+		}
+	}
+
+	return trending, nil
+}
@@ -0,0 +1,50 @@
+package textService
+
+import (
+	"context"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"time"
+)
+
+// * GetView возвращает представление variant текущей ревизии текста, построенное render.
+// * Представления популярных текстов кэшируются в redis рядом с самим содержимым
+// * и удаляются вместе с ним, поэтому повторно render для них не вызывается.
+func (s *TextOperator) GetView(
+	ctx context.Context,
+	hash string,
+	viewer models.Viewer,
+	variant string,
+	render func(c *models.Content) (string, error),
+) (string, error) {
+	if view, expiresAt, _ := s.redis.View(ctx, hash, variant); view != "" {
+		if !expiresAt.After(time.Now()) {
+			return "", s.expired(ctx, hash, expiresAt)
+		}
+
+		if err := s.countView(ctx, hash); err != nil {
+			return "", err
+		}
+
+		return view, nil
+	}
+
+	c, err := s.GetContent(ctx, hash, 0, viewer)
+	if err != nil {
+		return "", err
+	}
+
+	if !models.IsText(c.ContentType) {
+		return "", storage.ErrBinaryContent
+	}
+
+	view, err := render(c)
+	if err != nil {
+		return "", err
+	}
+
+	// Содержимое попадает в кэш только у популярных неприватных текстов, SaveView это учитывает
+	_ = s.redis.SaveView(ctx, hash, variant, view)
+
+	return view, nil
+}
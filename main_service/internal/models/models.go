@@ -2,17 +2,260 @@ package models
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
 	"time"
 )
 
+// * ContentTypeText — тип обычного текста. Типы хранятся без параметров (charset и т.п.)
+const ContentTypeText = "text/plain"
+
+const (
+	VisibilityPublic   = "public"   // * виден всем и попадает в публичные списки
+	VisibilityUnlisted = "unlisted" // * доступен по хэшу, но нигде не перечисляется
+	VisibilityPrivate  = "private"  // * доступен только владельцу и по подписанной ссылке
+)
+
+const (
+	ModerationHidden  = "hidden"  // * скрыт автоматически после жалоб, ждёт модератора
+	ModerationRemoved = "removed" // * содержимое удалено модератором
+)
+
+// * причины жалоб
+var ReportReasons = []string{"spam", "malware", "phishing", "abuse", "illegal", "other"}
+
 type Paste struct {
+	Hash        string
+	OwnerID     int64 // * 0 — анонимный текст
+	Visibility  string
+	CurrentRev  int
+	ObjectKey   string // * ключ объекта текущей ревизии в MinIO
+	ContentType string // * MIME тип текущей ревизии
+	Size        int64  // * размер текущей ревизии в байтах
+	Digest      string // * sha256 текущей ревизии в hex, пусто у старых ревизий
+	ParentHash  string // * из какого текста сделан форк, пусто для оригинала
+	Language    string // * язык текста, пусто — обычный текст
+	Filename    string // * имя файла от автора, подсказка для определения языка
+	// * уверенность в языке от 0 до 1; 1 — язык указал автор
+	LanguageConfidence float64
+	Moderation         string // * пусто, ModerationHidden или ModerationRemoved
+	ModerationReason   string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time // * когда создана текущая ревизия
+	ExpiresAt          time.Time
+}
+
+// * ForkNode — узел дерева форков
+type ForkNode struct {
+	Hash      string
+	CreatedAt time.Time
+	Forks     []ForkNode
+}
+
+// * Revision — неизменяемая версия текста
+type Revision struct {
+	Hash        string
+	Rev         int
+	ObjectKey   string
+	ContentType string
+	Size        int64
+	Digest      string // * sha256 содержимого в hex, пусто у ревизий, сохранённых до появления поля
+	AuthorID    int64
+	CreatedAt   time.Time
+}
+
+// * File — файл многофайлового текста (gist) из манифеста
+type File struct {
+	Name      string
+	Language  string
+	ObjectKey string
+	Size      int64
+}
+
+// * FileInput — файл для сохранения в многофайловом тексте
+type FileInput struct {
+	Name     string
+	Language string
+	Content  string
+}
+
+// * Content — содержимое ревизии вместе с её MIME типом
+type Content struct {
+	Data        string
+	ContentType string
+	Language    string
+	Digest      string    // * sha256 содержимого в hex
+	Size        int64     // * размер содержимого в байтах
+	Visibility  string    // * видимость текста
+	Mutable     bool      // * владелец ещё может заменить это содержимое новой ревизией
+	CreatedAt   time.Time // * когда создана ревизия
+	ExpiresAt   time.Time // * когда истекает сам текст
+}
+
+// * Digest возвращает sha256 данных в hex
+func Digest(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// * IsText сообщает, можно ли отдавать содержимое типа contentType как текст в JSON
+func IsText(contentType string) bool {
+	return contentType == "" || strings.HasPrefix(contentType, "text/")
+}
+
+// * SearchHit — текст, найденный полнотекстовым поиском
+type SearchHit struct {
+	Hash      string
+	Language  string
+	CreatedAt time.Time
+	Body      string // * проиндексированное содержимое, из него строится Snippet
+	Snippet   string
+	Score     float64
+}
+
+// * Report — жалоба читателя на текст
+type Report struct {
+	ID          int64
+	Hash        string
+	ReporterKey string // * sha256 от пользователя или IP, по нему считаются разные жалобщики
+	ReporterID  int64  // * 0 — анонимная жалоба
+	Reason      string
+	Comment     string
+	CreatedAt   time.Time
+}
+
+// * ReportedPaste — текст в очереди модерации
+type ReportedPaste struct {
+	Paste
+	Reports        int
+	LastReportedAt time.Time
+}
+
+// * окна трендов
+const (
+	TrendingHour = "hour" // * текущий и предыдущий час
+	TrendingDay  = "day"  // * последние 24 часа
+	TrendingWeek = "week" // * последние 7 суток
+)
+
+// * TrendingPaste — текст в трендах и число его просмотров за окно
+type TrendingPaste struct {
 	Hash      string
+	Language  string
 	CreatedAt time.Time
-	ExpiresAt time.Time
+	Views     int64
+}
+
+// * PasteFilter — фильтры списка текстов для администратора; нулевые значения не фильтруют
+type PasteFilter struct {
+	CreatedFrom    time.Time
+	CreatedTo      time.Time
+	ExpiresFrom    time.Time
+	ExpiresTo      time.Time
+	MinSize        int64
+	MaxSize        int64
+	OwnerID        int64
+	IncludeExpired bool
+}
+
+// * AuditEntry — запись журнала действий администраторов
+type AuditEntry struct {
+	AdminID   int64
+	Action    string // * метод и шаблон маршрута, например POST /admin/pastes/{hash}/expire
+	Hash      string
+	Params    string // * query и тело запроса
+	Status    int
+	RequestID string
+	CreatedAt time.Time
+}
+
+// * PasteInput — данные для сохранения нового текста.
+// * Если заданы Files, Text игнорируется и текст сохраняется как набор файлов.
+// * ContentType задаётся для загруженных файлов, пустой означает обычный текст.
+type PasteInput struct {
+	Text        string
+	ContentType string
+	Language    string // * пусто — определить по содержимому
+	Filename    string // * помогает определить язык по расширению
+	Files       []FileInput
+	TTLDays     int
+	OwnerID     int64
+	Visibility  string
+	SecretsMode string // * reject или redact; пусто — как в конфиге
+}
+
+// * Viewer — кто читает текст
+type Viewer struct {
+	UserID int64 // * 0 — анонимный читатель
+	Signed bool  // * предъявлена действующая подписанная ссылка
+}
+
+// * CanRead проверяет, может ли viewer читать текст p
+func (p *Paste) CanRead(v Viewer) bool {
+	if p.Visibility != VisibilityPrivate {
+		return true
+	}
+
+	return v.Signed || (p.OwnerID != 0 && p.OwnerID == v.UserID)
+}
+
+// * CanList проверяет, можно ли показывать текст p viewer'у в списках (например, в дереве форков)
+func (p *Paste) CanList(v Viewer) bool {
+	return p.Visibility == VisibilityPublic || (p.OwnerID != 0 && p.OwnerID == v.UserID)
+}
+
+type User struct {
+	ID        int64
+	Name      string
+	IsAdmin   bool
+	CreatedAt time.Time
+}
+
+// * RateLimitResult — состояние token bucket после попытки взять токен
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration // через сколько появится следующий токен, если запрос отклонён
+	Reset      time.Duration // через сколько bucket заполнится полностью
+}
+
+// * Moderator — жалобы на тексты и их модерация
+type Moderator interface {
+	Report(ctx context.Context, hash string, viewer Viewer, report Report) error
+	ReportedPastes(ctx context.Context, limit, offset int) ([]ReportedPaste, int, error)
+	ReviewPaste(ctx context.Context, hash string) (*Paste, []Report, *Content, error)
+	TakeDown(ctx context.Context, hash, reason string) error
+	DismissReports(ctx context.Context, hash string) error
+}
+
+// * Admin — управление текстами для администраторов
+type Admin interface {
+	ListPastes(ctx context.Context, f PasteFilter, limit, offset int) ([]Paste, int, error)
+	ExpireText(ctx context.Context, hash string) error
+	ExtendText(ctx context.Context, hash string, days int) (time.Time, error)
+	PurgeText(ctx context.Context, hash string) error
 }
 
 type TextOperator interface {
-	SaveText(ctx context.Context, text string, ttl int) (string, error)
-	GetText(ctx context.Context, hash string) (string, error)
+	SaveText(ctx context.Context, in PasteInput) (string, error)
+	GetText(ctx context.Context, hash string, rev int, viewer Viewer) (string, error)
+	GetContent(ctx context.Context, hash string, rev int, viewer Viewer) (*Content, error)
+	ContentInfo(ctx context.Context, hash string, rev int, viewer Viewer) (*Content, error)
+	GetView(ctx context.Context, hash string, viewer Viewer, variant string, render func(c *Content) (string, error)) (string, error)
+	GetEncoded(ctx context.Context, hash string, c *Content, encoding string, encode func(data string) (string, error)) (string, error)
+	UpdateOwnText(ctx context.Context, hash string, ownerID int64, text string) (int, error)
+	Revisions(ctx context.Context, hash string, viewer Viewer) ([]Revision, error)
+	ForkText(ctx context.Context, hash string, rev int, viewer Viewer, in PasteInput) (string, error)
+	Metadata(ctx context.Context, hash string, viewer Viewer) (*Paste, []ForkNode, error)
+	Files(ctx context.Context, hash string, viewer Viewer) ([]File, error)
+	FileText(ctx context.Context, hash, name string, viewer Viewer) (*File, string, error)
+	ReadFiles(ctx context.Context, hash string, viewer Viewer, fn func(f File, content string) error) error
+	Search(ctx context.Context, q string, viewer Viewer, limit, offset int) ([]SearchHit, error)
+	Trending(ctx context.Context, window string, limit int) ([]TrendingPaste, error)
 	DeleteText(ctx context.Context, hash string) error
+	OwnMetadata(ctx context.Context, hash string, ownerID int64) (*Paste, error)
+	DeleteOwnText(ctx context.Context, hash string, ownerID int64) error
+	ExtendOwnText(ctx context.Context, hash string, ownerID int64, days int) (time.Time, error)
+	ListByOwner(ctx context.Context, ownerID int64, limit, offset int) ([]Paste, int, error)
 }
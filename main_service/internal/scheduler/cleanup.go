@@ -2,40 +2,63 @@ package cleanup
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"sync"
 	"time"
 )
 
+// * ErrBusy — очистка или сверка уже выполняется
+var ErrBusy = errors.New("cleanup is already running")
+
+// * reconcileGrace — объекты моложе этого не считаются осиротевшими:
+// * текст сначала пишется в MinIO и только потом в MySQL
+const reconcileGrace = time.Hour
+
+// * popularityDecay — во сколько раз за сутки уменьшается популярность: вес просмотра падает вдвое каждый день
+const popularityDecay = 0.5
+
 type Storage interface {
 	GetExpired(ctx context.Context) ([]string, error)
-	DeleteByHash(ctx context.Context, hash string) error
+	ObjectRefs(ctx context.Context, key string) (int, error)
+}
+
+// * Texts удаляет текст из всех хранилищ (redis, MySQL, все ревизии в MinIO)
+type Texts interface {
+	DeleteText(ctx context.Context, hash string) error
 }
 
-type FileStorage interface {
-	DeleteFile(ctx context.Context, hash string) error
+// * Ranking — рейтинг популярности текстов в redis
+type Ranking interface {
+	DecayPopularity(ctx context.Context, factor float64) error
 }
 
-type Cache interface {
-	DeleteText(ctx context.Context, hash string) error
+// * Blobs — объекты текстов в MinIO
+type Blobs interface {
+	ListFiles(ctx context.Context, modifiedBefore time.Time) ([]string, error)
+	DeleteFile(ctx context.Context, key string) error
 }
 
 type Cleaner struct {
-	db    Storage
-	files FileStorage
-	cache Cache
-	log   *slog.Logger
+	db      Storage
+	texts   Texts
+	blobs   Blobs
+	ranking Ranking
+	log     *slog.Logger
+	mu      sync.Mutex
 }
 
-func New(db Storage, files FileStorage, cache Cache, log *slog.Logger) *Cleaner {
+func New(db Storage, texts Texts, blobs Blobs, ranking Ranking, log *slog.Logger) *Cleaner {
 	return &Cleaner{
-		db:    db,
-		files: files,
-		cache: cache,
-		log:   log,
+		db:      db,
+		texts:   texts,
+		blobs:   blobs,
+		ranking: ranking,
+		log:     log,
 	}
 }
 
-// * Start запускает проверку в указанное время суток.
+// * Start запускает проверку и затухание популярности в указанное время суток.
 func (c *Cleaner) Start(ctx context.Context, hour, minute int) {
 	go func() {
 		for {
@@ -49,44 +72,99 @@ func (c *Cleaner) Start(ctx context.Context, hour, minute int) {
 			case <-ctx.Done():
 				return
 			case <-time.After(time.Until(next)):
-				c.run(ctx)
+				if _, err := c.Run(ctx); err != nil {
+					c.log.Error("Cleanup task failed", slog.Any("error", err))
+				}
+				// Не в Run: ручной запуск очистки не должен лишний раз уменьшать рейтинг
+				if err := c.ranking.DecayPopularity(ctx, popularityDecay); err != nil {
+					c.log.Error("Popularity decay failed", slog.Any("error", err))
+				}
 			}
 		}
 	}()
 }
 
-// * run выполняет очистку.
-func (c *Cleaner) run(ctx context.Context) {
+// * Busy сообщает, выполняется ли сейчас очистка или сверка
+func (c *Cleaner) Busy() bool {
+	if !c.mu.TryLock() {
+		return true
+	}
+	c.mu.Unlock()
+
+	return false
+}
+
+// * Run удаляет истёкшие тексты и возвращает, сколько удалено.
+// * Если очистка или сверка уже идёт, возвращает ErrBusy.
+func (c *Cleaner) Run(ctx context.Context) (int, error) {
+	if !c.mu.TryLock() {
+		return 0, ErrBusy
+	}
+	defer c.mu.Unlock()
+
 	c.log.Info("Starting cleanup task...")
 
 	expired, err := c.db.GetExpired(ctx)
 	if err != nil {
-		c.log.Error("Failed to get expired hashes", slog.Any("error", err))
-		return
+		return 0, err
 	}
 
 	if len(expired) == 0 {
 		c.log.Info("No expired entries found")
-		return
+		return 0, nil
 	}
 
+	deleted := 0
 	for _, hash := range expired {
-		if err := c.cache.DeleteText(ctx, hash); err != nil {
-			c.log.Error("Failed to delete from Redis", slog.String("hash", hash), slog.Any("error", err))
+		if err := c.texts.DeleteText(ctx, hash); err != nil {
+			c.log.Error("Failed to delete expired paste", slog.String("hash", hash), slog.Any("error", err))
+			continue
 		}
+		deleted++
 
-		if err := c.db.DeleteByHash(ctx, hash); err != nil {
-			c.log.Error("Failed to delete from MySQL", slog.String("hash", hash), slog.Any("error", err))
+		c.log.Info("Deleted expired paste", slog.String("hash", hash))
+	}
+
+	c.log.Info("Cleanup task completed", slog.Int("deleted", deleted))
+
+	return deleted, nil
+}
+
+// * Reconcile удаляет из MinIO объекты, на которые не ссылается ни одна ревизия или файл
+// * (например, оставшиеся после сбоя между записью в MinIO и в MySQL). Возвращает, сколько удалено.
+func (c *Cleaner) Reconcile(ctx context.Context) (int, error) {
+	if !c.mu.TryLock() {
+		return 0, ErrBusy
+	}
+	defer c.mu.Unlock()
+
+	c.log.Info("Starting reconciliation...")
+
+	keys, err := c.blobs.ListFiles(ctx, time.Now().Add(-reconcileGrace))
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, key := range keys {
+		refs, err := c.db.ObjectRefs(ctx, key)
+		if err != nil {
+			return deleted, err
+		}
+		if refs > 0 {
 			continue
 		}
 
-		if err := c.files.DeleteFile(ctx, hash); err != nil {
-			c.log.Error("Failed to delete from MinIO", slog.String("hash", hash), slog.Any("error", err))
+		if err := c.blobs.DeleteFile(ctx, key); err != nil {
+			c.log.Error("Failed to delete orphaned object", slog.String("key", key), slog.Any("error", err))
 			continue
 		}
+		deleted++
 
-		c.log.Info("Deleted expired paste", slog.String("hash", hash))
+		c.log.Info("Deleted orphaned object", slog.String("key", key))
 	}
 
-	c.log.Info("Cleanup task completed", slog.Int("deleted", len(expired)))
+	c.log.Info("Reconciliation completed", slog.Int("checked", len(keys)), slog.Int("deleted", deleted))
+
+	return deleted, nil
 }
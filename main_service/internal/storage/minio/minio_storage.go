@@ -5,11 +5,19 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("main_service/internal/storage/minio")
+
 type MinIOStorage struct {
 	client *minio.Client
 	bucket string
@@ -43,62 +51,129 @@ func New(ctx context.Context, endpoint, accessKey, secretKey, bucket string, use
 	}, nil
 }
 
-// * SaveStringAsFile сохраняет текст в storage
-func (m *MinIOStorage) SaveStringAsFile(ctx context.Context, hash, content string) error {
+// * SaveStringAsFile сохраняет текст в storage под ключом key
+func (m *MinIOStorage) SaveStringAsFile(ctx context.Context, key, content string) error {
 	const op = "minio.SaveStringAsFile"
 
-	data := bytes.NewReader([]byte(content))
+	if err := m.SaveObject(ctx, key, content, "text/plain"); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// * SaveObject сохраняет произвольные (в том числе бинарные) данные под ключом key с типом contentType
+func (m *MinIOStorage) SaveObject(ctx context.Context, key, content, contentType string) error {
+	const op = "minio.SaveObject"
+
+	ctx, span := m.startSpan(ctx, "minio.PutObject", key)
+	defer span.End()
 
-	_, err := m.client.PutObject(ctx, m.bucket, hash+".txt", data, int64(data.Len()), minio.PutObjectOptions{
-		ContentType: "text/plain",
+	data := strings.NewReader(content)
+
+	_, err := m.client.PutObject(ctx, m.bucket, key, data, data.Size(), minio.PutObjectOptions{
+		ContentType: contentType,
 	})
 	if err != nil {
+		recordError(span, err)
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	return nil
 }
 
-// * GetString возвращает содержимое объекта как строку.
-func (m *MinIOStorage) GetString(ctx context.Context, hash string) (string, error) {
+// * GetString возвращает содержимое объекта key как строку.
+func (m *MinIOStorage) GetString(ctx context.Context, key string) (string, error) {
 	const op = "minio.GetString"
 
-	obj, err := m.client.GetObject(ctx, m.bucket, hash+".txt", minio.GetObjectOptions{})
+	ctx, span := m.startSpan(ctx, "minio.GetObject", key)
+	defer span.End()
+
+	obj, err := m.client.GetObject(ctx, m.bucket, key, minio.GetObjectOptions{})
 	if err != nil {
+		recordError(span, err)
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 	defer obj.Close()
 
 	buf := new(bytes.Buffer)
 	if _, err := io.Copy(buf, obj); err != nil {
+		recordError(span, err)
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
+	span.SetAttributes(attribute.Int("minio.object.size", buf.Len()))
+
 	return buf.String(), nil
 }
 
-// * DeleteFile удаляет объект по хэшу.
-func (m *MinIOStorage) DeleteFile(ctx context.Context, hash string) error {
+// * DeleteFile удаляет объект key.
+func (m *MinIOStorage) DeleteFile(ctx context.Context, key string) error {
 	const op = "minio.DeleteFile"
 
-	err := m.client.RemoveObject(ctx, m.bucket, hash+".txt", minio.RemoveObjectOptions{})
+	ctx, span := m.startSpan(ctx, "minio.RemoveObject", key)
+	defer span.End()
+
+	err := m.client.RemoveObject(ctx, m.bucket, key, minio.RemoveObjectOptions{})
 	if err != nil {
+		recordError(span, err)
 		return fmt.Errorf("%s: %w", op, err)
 	}
 	return nil
 }
 
-// * ListFiles возвращает все объекты в бакете.
-func (m *MinIOStorage) ListFiles(ctx context.Context) ([]string, error) {
+// * ListFiles возвращает объекты в бакете, изменённые раньше modifiedBefore; нулевое время — все объекты.
+func (m *MinIOStorage) ListFiles(ctx context.Context, modifiedBefore time.Time) ([]string, error) {
 	const op = "minio.ListFiles"
 
+	ctx, span := m.startSpan(ctx, "minio.ListObjects", "")
+	defer span.End()
+
 	var files []string
 	for obj := range m.client.ListObjects(ctx, m.bucket, minio.ListObjectsOptions{Recursive: true}) {
 		if obj.Err != nil {
+			recordError(span, obj.Err)
 			return nil, fmt.Errorf("%s: %w", op, obj.Err)
 		}
+		if !modifiedBefore.IsZero() && !obj.LastModified.Before(modifiedBefore) {
+			continue
+		}
 		files = append(files, obj.Key)
 	}
 
 	return files, nil
 }
+
+// * Ping проверяет, что MinIO доступен и бакет существует.
+func (m *MinIOStorage) Ping(ctx context.Context) error {
+	const op = "minio.Ping"
+
+	exists, err := m.client.BucketExists(ctx, m.bucket)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		return fmt.Errorf("%s: bucket %q does not exist", op, m.bucket)
+	}
+
+	return nil
+}
+
+// * startSpan открывает client span для операции с бакетом
+func (m *MinIOStorage) startSpan(ctx context.Context, name, key string) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{attribute.String("minio.bucket", m.bucket)}
+	if key != "" {
+		attrs = append(attrs, attribute.String("minio.object.key", key))
+	}
+
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,139 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+	"strings"
+	"time"
+)
+
+// * ListPastes возвращает страницу текстов по фильтрам администратора, новые первыми, и их общее количество.
+// * Размер — размер текущей ревизии.
+func (r *Repository) ListPastes(ctx context.Context, f models.PasteFilter, limit, offset int) ([]models.Paste, int, error) {
+	const op = "mysql.ListPastes"
+
+	var (
+		where []string
+		args  []any
+	)
+	add := func(cond string, arg any) {
+		where = append(where, cond)
+		args = append(args, arg)
+	}
+
+	if !f.CreatedFrom.IsZero() {
+		add("p.created_at >= ?", f.CreatedFrom)
+	}
+	if !f.CreatedTo.IsZero() {
+		add("p.created_at < ?", f.CreatedTo)
+	}
+	if !f.ExpiresFrom.IsZero() {
+		add("p.expires_at >= ?", f.ExpiresFrom)
+	}
+	if !f.ExpiresTo.IsZero() {
+		add("p.expires_at < ?", f.ExpiresTo)
+	}
+	if f.MinSize > 0 {
+		add("COALESCE(r.size, 0) >= ?", f.MinSize)
+	}
+	if f.MaxSize > 0 {
+		add("COALESCE(r.size, 0) <= ?", f.MaxSize)
+	}
+	if f.OwnerID != 0 {
+		add("p.owner_id = ?", f.OwnerID)
+	}
+	if !f.IncludeExpired {
+		where = append(where, "p.expires_at > UTC_TIMESTAMP()")
+	}
+
+	from := `FROM pastes p
+		LEFT JOIN paste_revisions r ON r.paste_hash = p.hash AND r.rev = p.current_rev`
+	if len(where) > 0 {
+		from += "\n\t\tWHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) "+from, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `SELECT p.hash, COALESCE(p.owner_id, 0), p.visibility, p.current_rev,
+			COALESCE(r.content_type, 'text/plain'), COALESCE(r.size, 0), p.language, p.moderation,
+			p.created_at, p.expires_at ` + from + `
+		ORDER BY p.created_at DESC, p.id DESC
+		LIMIT ? OFFSET ?`
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	pastes := make([]models.Paste, 0, limit)
+	for rows.Next() {
+		var p models.Paste
+		if err := rows.Scan(&p.Hash, &p.OwnerID, &p.Visibility, &p.CurrentRev, &p.ContentType, &p.Size,
+			&p.Language, &p.Moderation, &p.CreatedAt, &p.ExpiresAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", op, err)
+		}
+		pastes = append(pastes, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pastes, total, nil
+}
+
+// * ExtendExpiresAt продлевает текст на days дней от текущего времени истечения,
+// * а для уже истёкшего — от текущего момента. Возвращает новое время истечения.
+func (r *Repository) ExtendExpiresAt(ctx context.Context, hash string, days int) (time.Time, error) {
+	const op = "mysql.ExtendExpiresAt"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE pastes SET expires_at = DATE_ADD(GREATEST(expires_at, UTC_TIMESTAMP()), INTERVAL ? DAY) WHERE hash = ?`
+
+	if _, err := tx.ExecContext(ctx, query, days, hash); err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var expiresAt time.Time
+	if err := tx.QueryRowContext(ctx, `SELECT expires_at FROM pastes WHERE hash = ?`, hash).Scan(&expiresAt); err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, storage.ErrTextNotFound
+		}
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return expiresAt, nil
+}
+
+// * AddAudit записывает действие администратора в журнал
+func (r *Repository) AddAudit(ctx context.Context, e *models.AuditEntry) error {
+	const op = "mysql.AddAudit"
+
+	query := `INSERT INTO admin_audit_log (admin_id, action, paste_hash, params, status, request_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	if _, err := r.db.ExecContext(ctx, query,
+		e.AdminID, e.Action, nullString(e.Hash), e.Params, e.Status, e.RequestID, e.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
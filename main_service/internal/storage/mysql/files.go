@@ -0,0 +1,70 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+)
+
+// * Files возвращает манифест файлов текста в порядке сохранения.
+// * У обычного (однофайлового) текста манифест пустой.
+func (r *Repository) Files(ctx context.Context, hash string) ([]models.File, error) {
+	const op = "mysql.Files"
+
+	query := `SELECT filename, language, object_key, size FROM paste_files
+		WHERE paste_hash = ? ORDER BY position`
+
+	rows, err := r.db.QueryContext(ctx, query, hash)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var files []models.File
+	for rows.Next() {
+		var f models.File
+		if err := rows.Scan(&f.Name, &f.Language, &f.ObjectKey, &f.Size); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		files = append(files, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return files, nil
+}
+
+// * File возвращает файл name из манифеста текста
+func (r *Repository) File(ctx context.Context, hash, name string) (*models.File, error) {
+	const op = "mysql.File"
+
+	query := `SELECT filename, language, object_key, size FROM paste_files
+		WHERE paste_hash = ? AND filename = ?`
+
+	var f models.File
+	if err := r.db.QueryRowContext(ctx, query, hash, name).Scan(&f.Name, &f.Language, &f.ObjectKey, &f.Size); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, storage.ErrFileNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &f, nil
+}
+
+func insertFiles(ctx context.Context, tx *sql.Tx, hash string, files []models.File) error {
+	query := `INSERT INTO paste_files (paste_hash, position, filename, language, object_key, size)
+		VALUES (?, ?, ?, ?, ?, ?)`
+
+	for i, f := range files {
+		if _, err := tx.ExecContext(ctx, query, hash, i, f.Name, f.Language, f.ObjectKey, f.Size); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"main_service/internal/models"
+)
+
+const (
+	maxForkDepth = 32  // * глубже дерево форков не обходим
+	maxForks     = 500 // * сколько потомков возвращаем не больше
+)
+
+// * Forks возвращает неистёкшие тексты, производные от hash (форки, форки форков и т.д.),
+// * в порядке создания
+func (r *Repository) Forks(ctx context.Context, hash string) ([]models.Paste, error) {
+	const op = "mysql.Forks"
+
+	query := `WITH RECURSIVE tree AS (
+			SELECT hash, parent_hash, owner_id, visibility, created_at, expires_at, 1 AS depth
+			FROM pastes WHERE parent_hash = ?
+			UNION ALL
+			SELECT p.hash, p.parent_hash, p.owner_id, p.visibility, p.created_at, p.expires_at, t.depth + 1
+			FROM pastes p JOIN tree t ON p.parent_hash = t.hash
+			WHERE t.depth < ?
+		)
+		SELECT hash, parent_hash, owner_id, visibility, created_at, expires_at FROM tree
+		WHERE expires_at > UTC_TIMESTAMP()
+		ORDER BY created_at, hash
+		LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, query, hash, maxForkDepth, maxForks)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var forks []models.Paste
+	for rows.Next() {
+		var (
+			p       models.Paste
+			ownerID sql.NullInt64
+		)
+		if err := rows.Scan(&p.Hash, &p.ParentHash, &ownerID, &p.Visibility, &p.CreatedAt, &p.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		p.OwnerID = ownerID.Int64
+
+		forks = append(forks, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return forks, nil
+}
+
+// * ObjectRefs возвращает, сколько ревизий и файлов ссылается на объект key в MinIO
+func (r *Repository) ObjectRefs(ctx context.Context, key string) (int, error) {
+	const op = "mysql.ObjectRefs"
+
+	query := `SELECT (SELECT COUNT(*) FROM paste_revisions WHERE object_key = ?)
+		+ (SELECT COUNT(*) FROM paste_files WHERE object_key = ?)`
+
+	var n int
+	if err := r.db.QueryRowContext(ctx, query, key, key).Scan(&n); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return n, nil
+}
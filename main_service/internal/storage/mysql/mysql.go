@@ -8,7 +8,9 @@ import (
 	"main_service/internal/storage"
 	"time"
 
+	"github.com/XSAM/otelsql"
 	_ "github.com/go-sql-driver/mysql"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 )
 
 type Repository struct {
@@ -18,7 +20,7 @@ type Repository struct {
 func New(dsn string) (*Repository, error) {
 	const op = "mysql.New"
 
-	db, err := sql.Open("mysql", dsn)
+	db, err := otelsql.Open("mysql", dsn, otelsql.WithAttributes(semconv.DBSystemNameMySQL))
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -30,39 +32,72 @@ func New(dsn string) (*Repository, error) {
 	return &Repository{db: db}, nil
 }
 
-// * SaveMetadata сохраняет метаданные для текста
-// * ttlDays — время жизни записи в днях
-func (r *Repository) SaveMetadata(ctx context.Context, hash string, ttlDays int) error {
+// * SaveMetadata сохраняет метаданные для текста вместе с его первой ревизией и манифестом файлов.
+// * CreatedAt и ExpiresAt должны быть заполнены вызывающим кодом.
+func (r *Repository) SaveMetadata(ctx context.Context, p *models.Paste, rev *models.Revision, files []models.File) error {
 	const op = "mysql.SaveMetadata"
 
-	now := time.Now().UTC()
-	expires := now.AddDate(0, 0, ttlDays)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO pastes (hash, owner_id, visibility, current_rev, last_rev, parent_hash,
+			language, language_confidence, filename, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	query := `INSERT INTO pastes (hash, created_at, expires_at) VALUES (?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, query,
+		p.Hash, nullID(p.OwnerID), p.Visibility, rev.Rev, rev.Rev, nullString(p.ParentHash),
+		p.Language, p.LanguageConfidence, p.Filename, p.CreatedAt, p.ExpiresAt,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
 
-	if _, err := r.db.ExecContext(ctx, query, hash, now, expires); err != nil {
+	if err := insertRevision(ctx, tx, rev); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := insertFiles(ctx, tx, p.Hash, files); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	return nil
 }
 
-// * GetByHash возвращает метаданные для текста по хэшу
+// * GetByHash возвращает метаданные для текста по хэшу.
+// * Для истёкшего текста вместе с *storage.ExpiredError возвращаются и метаданные, чтобы можно было проверить доступ.
 func (r *Repository) GetByHash(ctx context.Context, hash string) (*models.Paste, error) {
 	const op = "mysql.GetByHash"
 
-	query := `SELECT hash, created_at, expires_at FROM pastes WHERE hash = ?`
-
-	var p models.Paste
-	if err := r.db.QueryRowContext(ctx, query, hash).Scan(&p.Hash, &p.CreatedAt, &p.ExpiresAt); err != nil {
+	query := `SELECT p.hash, p.owner_id, p.visibility, p.current_rev, COALESCE(r.object_key, ''),
+			COALESCE(r.content_type, 'text/plain'), COALESCE(r.size, 0), COALESCE(r.digest, ''), COALESCE(p.parent_hash, ''), p.language, p.language_confidence, p.filename,
+			p.moderation, p.moderation_reason, p.created_at, COALESCE(r.created_at, p.created_at), p.expires_at
+		FROM pastes p
+		LEFT JOIN paste_revisions r ON r.paste_hash = p.hash AND r.rev = p.current_rev
+		WHERE p.hash = ?`
+
+	var (
+		p       models.Paste
+		ownerID sql.NullInt64
+	)
+	if err := r.db.QueryRowContext(ctx, query, hash).Scan(
+		&p.Hash, &ownerID, &p.Visibility, &p.CurrentRev, &p.ObjectKey, &p.ContentType, &p.Size, &p.Digest, &p.ParentHash, &p.Language, &p.LanguageConfidence, &p.Filename,
+		&p.Moderation, &p.ModerationReason, &p.CreatedAt, &p.UpdatedAt, &p.ExpiresAt,
+	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, storage.ErrTextNotFound
 		}
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	p.OwnerID = ownerID.Int64
 
 	if !p.ExpiresAt.IsZero() && p.ExpiresAt.Before(time.Now().UTC()) {
-		return nil, storage.ErrTTLIsExpired
+		return &p, &storage.ExpiredError{ExpiresAt: p.ExpiresAt}
 	}
 
 	return &p, nil
@@ -92,7 +127,77 @@ func (r *Repository) GetExpired(ctx context.Context) ([]string, error) {
 	return hashes, nil
 }
 
-// * DeleteByHash удаляет метаданные по хэшу
+// * ListByOwner возвращает страницу текстов пользователя (новые первыми) и их общее количество.
+// * Истёкшие тексты не возвращаются.
+func (r *Repository) ListByOwner(ctx context.Context, ownerID int64, limit, offset int) ([]models.Paste, int, error) {
+	const op = "mysql.ListByOwner"
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM pastes WHERE owner_id = ? AND expires_at > UTC_TIMESTAMP()`
+	if err := r.db.QueryRowContext(ctx, countQuery, ownerID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `SELECT hash, visibility, current_rev, created_at, expires_at FROM pastes
+		WHERE owner_id = ? AND expires_at > UTC_TIMESTAMP()
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?`
+
+	rows, err := r.db.QueryContext(ctx, query, ownerID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	pastes := make([]models.Paste, 0, limit)
+	for rows.Next() {
+		p := models.Paste{OwnerID: ownerID}
+		if err := rows.Scan(&p.Hash, &p.Visibility, &p.CurrentRev, &p.CreatedAt, &p.ExpiresAt); err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", op, err)
+		}
+		pastes = append(pastes, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pastes, total, nil
+}
+
+// * UpdateExpiresAt меняет время истечения текста
+func (r *Repository) UpdateExpiresAt(ctx context.Context, hash string, expiresAt time.Time) error {
+	const op = "mysql.UpdateExpiresAt"
+
+	query := `UPDATE pastes SET expires_at = ? WHERE hash = ?`
+
+	res, err := r.db.ExecContext(ctx, query, expiresAt, hash)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return storage.ErrTextNotFound
+	}
+
+	return nil
+}
+
+// * UpdateLanguage меняет язык текста и уверенность в нём
+func (r *Repository) UpdateLanguage(ctx context.Context, hash, language string, confidence float64) error {
+	const op = "mysql.UpdateLanguage"
+
+	query := `UPDATE pastes SET language = ?, language_confidence = ? WHERE hash = ?`
+
+	if _, err := r.db.ExecContext(ctx, query, language, confidence, hash); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// * DeleteByHash удаляет метаданные по хэшу, ревизии и манифест файлов удаляются каскадно.
+// * У форков текста parent_hash обнуляется.
 func (r *Repository) DeleteByHash(ctx context.Context, hash string) error {
 	const op = "mysqlRepository.DeleteByHash"
 
@@ -105,7 +210,28 @@ func (r *Repository) DeleteByHash(ctx context.Context, hash string) error {
 	return nil
 }
 
+// * Ping проверяет доступность базы данных
+func (r *Repository) Ping(ctx context.Context) error {
+	const op = "mysql.Ping"
+
+	if err := r.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
 // * Close закрывает соединение с базой данных
 func (r *Repository) Close() error {
 	return r.db.Close()
 }
+
+// * nullID превращает нулевой идентификатор в NULL
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
+
+// * nullString превращает пустую строку в NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,189 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+)
+
+// * AddReport сохраняет жалобу и возвращает число разных жалобщиков на текст.
+// * Повторная жалоба того же жалобщика игнорируется.
+func (r *Repository) AddReport(ctx context.Context, rep *models.Report) (int, error) {
+	const op = "mysql.AddReport"
+
+	query := `INSERT IGNORE INTO paste_reports (paste_hash, reporter_key, reporter_id, reason, comment, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`
+
+	if _, err := r.db.ExecContext(ctx, query,
+		rep.Hash, rep.ReporterKey, nullID(rep.ReporterID), rep.Reason, rep.Comment, rep.CreatedAt,
+	); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var n int
+	if err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM paste_reports WHERE paste_hash = ?`, rep.Hash,
+	).Scan(&n); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return n, nil
+}
+
+// * Reports возвращает жалобы на текст, новые первыми
+func (r *Repository) Reports(ctx context.Context, hash string) ([]models.Report, error) {
+	const op = "mysql.Reports"
+
+	query := `SELECT id, paste_hash, reporter_key, COALESCE(reporter_id, 0), reason, comment, created_at
+		FROM paste_reports
+		WHERE paste_hash = ?
+		ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, hash)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var reports []models.Report
+	for rows.Next() {
+		var rep models.Report
+		if err := rows.Scan(&rep.ID, &rep.Hash, &rep.ReporterKey, &rep.ReporterID, &rep.Reason, &rep.Comment, &rep.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		reports = append(reports, rep)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return reports, nil
+}
+
+// * ReportedPastes возвращает очередь модерации: неистёкшие тексты с жалобами, кроме уже удалённых.
+// * Скрытые автоматически идут первыми, затем по числу жалоб.
+func (r *Repository) ReportedPastes(ctx context.Context, limit, offset int) ([]models.ReportedPaste, int, error) {
+	const op = "mysql.ReportedPastes"
+
+	var total int
+	countQuery := `SELECT COUNT(DISTINCT rp.paste_hash)
+		FROM paste_reports rp
+		JOIN pastes p ON p.hash = rp.paste_hash
+		WHERE p.moderation <> ? AND p.expires_at > UTC_TIMESTAMP()`
+	if err := r.db.QueryRowContext(ctx, countQuery, models.ModerationRemoved).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `SELECT p.hash, COALESCE(p.owner_id, 0), p.visibility, p.moderation, p.created_at, p.expires_at,
+			COUNT(*) AS reports, MAX(rp.created_at) AS last_reported_at
+		FROM paste_reports rp
+		JOIN pastes p ON p.hash = rp.paste_hash
+		WHERE p.moderation <> ? AND p.expires_at > UTC_TIMESTAMP()
+		GROUP BY p.hash, p.owner_id, p.visibility, p.moderation, p.created_at, p.expires_at
+		ORDER BY p.moderation = ? DESC, reports DESC, last_reported_at DESC
+		LIMIT ? OFFSET ?`
+
+	rows, err := r.db.QueryContext(ctx, query, models.ModerationRemoved, models.ModerationHidden, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	pastes := make([]models.ReportedPaste, 0, limit)
+	for rows.Next() {
+		var p models.ReportedPaste
+		if err := rows.Scan(&p.Hash, &p.OwnerID, &p.Visibility, &p.Moderation, &p.CreatedAt, &p.ExpiresAt,
+			&p.Reports, &p.LastReportedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", op, err)
+		}
+		pastes = append(pastes, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pastes, total, nil
+}
+
+// * DeleteReports удаляет все жалобы на текст
+func (r *Repository) DeleteReports(ctx context.Context, hash string) error {
+	const op = "mysql.DeleteReports"
+
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM paste_reports WHERE paste_hash = ?`, hash); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// * SetModeration меняет статус модерации текста; пустой status возвращает текст читателям
+func (r *Repository) SetModeration(ctx context.Context, hash, status, reason string) error {
+	const op = "mysql.SetModeration"
+
+	query := `UPDATE pastes SET moderation = ?, moderation_reason = ? WHERE hash = ?`
+
+	res, err := r.db.ExecContext(ctx, query, status, reason, hash)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		// Ноль строк бывает и когда статус не изменился, поэтому проверяем существование
+		var exists bool
+		if err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM pastes WHERE hash = ?)`, hash).Scan(&exists); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if !exists {
+			return storage.ErrTextNotFound
+		}
+	}
+
+	return nil
+}
+
+// * TakeDown помечает текст удалённым модератором и удаляет его ревизии, файлы и документ поиска.
+// * Строка в pastes остаётся, чтобы GET отвечал 451 с причиной, а не 404.
+func (r *Repository) TakeDown(ctx context.Context, hash, reason string) error {
+	const op = "mysql.TakeDown"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var found int
+	if err := tx.QueryRowContext(ctx, `SELECT 1 FROM pastes WHERE hash = ? FOR UPDATE`, hash).Scan(&found); err != nil {
+		if err == sql.ErrNoRows {
+			return storage.ErrTextNotFound
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE pastes SET moderation = ?, moderation_reason = ? WHERE hash = ?`,
+		models.ModerationRemoved, reason, hash,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, query := range []string{
+		`DELETE FROM paste_revisions WHERE paste_hash = ?`,
+		`DELETE FROM paste_files WHERE paste_hash = ?`,
+		`DELETE FROM paste_search WHERE paste_hash = ?`,
+	} {
+		if _, err := tx.ExecContext(ctx, query, hash); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
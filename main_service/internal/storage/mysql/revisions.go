@@ -0,0 +1,145 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+)
+
+// * ReserveRevision атомарно выделяет номер следующей ревизии текста.
+// * Номер занимается до загрузки объекта в MinIO, поэтому параллельные правки
+// * никогда не пишут в один и тот же объект.
+func (r *Repository) ReserveRevision(ctx context.Context, hash string) (int, error) {
+	const op = "mysql.ReserveRevision"
+
+	// LAST_INSERT_ID(expr) возвращает новое значение в OK пакете, отдельный SELECT не нужен
+	res, err := r.db.ExecContext(ctx, `UPDATE pastes SET last_rev = LAST_INSERT_ID(last_rev + 1) WHERE hash = ?`, hash)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return 0, storage.ErrTextNotFound
+	}
+
+	rev, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(rev), nil
+}
+
+// * SaveRevision сохраняет ревизию и делает её текущей, если она новее текущей
+func (r *Repository) SaveRevision(ctx context.Context, rev *models.Revision) error {
+	const op = "mysql.SaveRevision"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := insertRevision(ctx, tx, rev); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `UPDATE pastes SET current_rev = GREATEST(current_rev, ?) WHERE hash = ?`
+
+	if _, err := tx.ExecContext(ctx, query, rev.Rev, rev.Hash); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// * Revision возвращает ревизию rev текста hash
+func (r *Repository) Revision(ctx context.Context, hash string, rev int) (*models.Revision, error) {
+	const op = "mysql.Revision"
+
+	query := `SELECT paste_hash, rev, object_key, content_type, size, digest, author_id, created_at
+		FROM paste_revisions WHERE paste_hash = ? AND rev = ?`
+
+	res, err := scanRevision(r.db.QueryRowContext(ctx, query, hash, rev))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, storage.ErrRevNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+// * Revisions возвращает все ревизии текста по возрастанию номера
+func (r *Repository) Revisions(ctx context.Context, hash string) ([]models.Revision, error) {
+	const op = "mysql.Revisions"
+
+	query := `SELECT paste_hash, rev, object_key, content_type, size, digest, author_id, created_at
+		FROM paste_revisions WHERE paste_hash = ? ORDER BY rev`
+
+	rows, err := r.db.QueryContext(ctx, query, hash)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var revs []models.Revision
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		revs = append(revs, *rev)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return revs, nil
+}
+
+func insertRevision(ctx context.Context, tx *sql.Tx, rev *models.Revision) error {
+	query := `INSERT INTO paste_revisions (paste_hash, rev, object_key, content_type, size, digest, author_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := tx.ExecContext(ctx, query,
+		rev.Hash, rev.Rev, rev.ObjectKey, contentType(rev.ContentType), rev.Size, rev.Digest, nullID(rev.AuthorID), rev.CreatedAt,
+	)
+
+	return err
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRevision(row scanner) (*models.Revision, error) {
+	var (
+		rev      models.Revision
+		authorID sql.NullInt64
+	)
+
+	if err := row.Scan(&rev.Hash, &rev.Rev, &rev.ObjectKey, &rev.ContentType, &rev.Size, &rev.Digest, &authorID, &rev.CreatedAt); err != nil {
+		return nil, err
+	}
+	rev.AuthorID = authorID.Int64
+
+	return &rev, nil
+}
+
+// * contentType подставляет text/plain для ревизий без явного типа
+func contentType(ct string) string {
+	if ct == "" {
+		return models.ContentTypeText
+	}
+
+	return ct
+}
@@ -0,0 +1,105 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"main_service/internal/models"
+)
+
+// * IndexText добавляет или заменяет документ текста в полнотекстовом индексе
+func (r *Repository) IndexText(ctx context.Context, hash, body string) error {
+	const op = "mysql.IndexText"
+
+	query := `INSERT INTO paste_search (paste_hash, body) VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE body = VALUES(body)`
+
+	if _, err := r.db.ExecContext(ctx, query, hash, body); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// * CopyIndex индексирует текст to тем же документом, что и from (форк без изменений)
+func (r *Repository) CopyIndex(ctx context.Context, from, to string) error {
+	const op = "mysql.CopyIndex"
+
+	query := `INSERT INTO paste_search (paste_hash, body)
+		SELECT ?, body FROM paste_search WHERE paste_hash = ?
+		ON DUPLICATE KEY UPDATE body = VALUES(body)`
+
+	if _, err := r.db.ExecContext(ctx, query, to, from); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// * Search ищет неистёкшие тексты по содержимому, самые релевантные первыми.
+// * Возвращаются только публичные тексты и тексты ownerID (0 — только публичные).
+func (r *Repository) Search(ctx context.Context, q string, ownerID int64, limit, offset int) ([]models.SearchHit, error) {
+	const op = "mysql.Search"
+
+	query := `SELECT p.hash, p.language, p.created_at, s.body,
+			MATCH(s.body) AGAINST(? IN NATURAL LANGUAGE MODE) AS score
+		FROM paste_search s
+		JOIN pastes p ON p.hash = s.paste_hash
+		WHERE MATCH(s.body) AGAINST(? IN NATURAL LANGUAGE MODE)
+			AND p.expires_at > UTC_TIMESTAMP()
+			AND p.moderation = ''
+			AND (p.visibility = ? OR p.owner_id = ?)
+		ORDER BY score DESC, p.created_at DESC
+		LIMIT ? OFFSET ?`
+
+	rows, err := r.db.QueryContext(ctx, query, q, q, models.VisibilityPublic, ownerID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var hits []models.SearchHit
+	for rows.Next() {
+		var h models.SearchHit
+		if err := rows.Scan(&h.Hash, &h.Language, &h.CreatedAt, &h.Body, &h.Score); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		hits = append(hits, h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return hits, nil
+}
+
+// * ActiveHashes возвращает страницу хэшей неистёкших текстов в порядке хэша, начиная после after
+func (r *Repository) ActiveHashes(ctx context.Context, after string, limit int) ([]string, error) {
+	const op = "mysql.ActiveHashes"
+
+	query := `SELECT hash FROM pastes
+		WHERE hash > ? AND expires_at > UTC_TIMESTAMP()
+		ORDER BY hash
+		LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, query, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		hashes = append(hashes, hash)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return hashes, nil
+}
@@ -0,0 +1,49 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"main_service/internal/models"
+	"strings"
+)
+
+// * PublicPastes возвращает те из hashes, что можно показывать в публичных списках:
+// * публичные, неистёкшие и не скрытые модератором
+func (r *Repository) PublicPastes(ctx context.Context, hashes []string) (map[string]models.Paste, error) {
+	const op = "mysql.PublicPastes"
+
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, 0, len(hashes)+1)
+	args = append(args, models.VisibilityPublic)
+	for _, h := range hashes {
+		args = append(args, h)
+	}
+
+	query := `SELECT hash, language, created_at, expires_at FROM pastes
+		WHERE visibility = ? AND moderation = '' AND expires_at > UTC_TIMESTAMP()
+			AND hash IN (?` + strings.Repeat(", ?", len(hashes)-1) + `)`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	pastes := make(map[string]models.Paste, len(hashes))
+	for rows.Next() {
+		p := models.Paste{Visibility: models.VisibilityPublic}
+		if err := rows.Scan(&p.Hash, &p.Language, &p.CreatedAt, &p.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		pastes[p.Hash] = p
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pastes, nil
+}
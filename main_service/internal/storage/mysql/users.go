@@ -0,0 +1,110 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"main_service/internal/models"
+	"main_service/internal/storage"
+
+	mysqlDriver "github.com/go-sql-driver/mysql"
+)
+
+const errDuplicateEntry = 1062
+
+// * CreateUser создаёт пользователя и возвращает его id
+func (r *Repository) CreateUser(ctx context.Context, name string, isAdmin bool) (int64, error) {
+	const op = "mysql.CreateUser"
+
+	query := `INSERT INTO users (name, is_admin) VALUES (?, ?)`
+
+	res, err := r.db.ExecContext(ctx, query, name, isAdmin)
+	if err != nil {
+		var mysqlErr *mysqlDriver.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == errDuplicateEntry {
+			return 0, storage.ErrUserExists
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// * UserByName возвращает пользователя по имени
+func (r *Repository) UserByName(ctx context.Context, name string) (*models.User, error) {
+	const op = "mysql.UserByName"
+
+	query := `SELECT id, name, is_admin, created_at FROM users WHERE name = ?`
+
+	var u models.User
+	if err := r.db.QueryRowContext(ctx, query, name).Scan(&u.ID, &u.Name, &u.IsAdmin, &u.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, storage.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &u, nil
+}
+
+// * CreateAPIKey сохраняет хэш нового API ключа пользователя.
+// * Сам ключ в базе не хранится, prefix нужен только для поиска ключа при отзыве.
+func (r *Repository) CreateAPIKey(ctx context.Context, userID int64, keyHash, prefix string) error {
+	const op = "mysql.CreateAPIKey"
+
+	query := `INSERT INTO api_keys (user_id, key_hash, prefix) VALUES (?, ?, ?)`
+
+	if _, err := r.db.ExecContext(ctx, query, userID, keyHash, prefix); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// * UserByAPIKey возвращает владельца действующего API ключа по его хэшу
+func (r *Repository) UserByAPIKey(ctx context.Context, keyHash string) (*models.User, error) {
+	const op = "mysql.UserByAPIKey"
+
+	query := `SELECT u.id, u.name, u.is_admin, u.created_at
+		FROM api_keys k JOIN users u ON u.id = k.user_id
+		WHERE k.key_hash = ? AND k.revoked_at IS NULL`
+
+	var u models.User
+	if err := r.db.QueryRowContext(ctx, query, keyHash).Scan(&u.ID, &u.Name, &u.IsAdmin, &u.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, storage.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &u, nil
+}
+
+// * RevokeAPIKey отзывает ключи с указанным префиксом и возвращает их количество
+func (r *Repository) RevokeAPIKey(ctx context.Context, prefix string) (int64, error) {
+	const op = "mysql.RevokeAPIKey"
+
+	query := `UPDATE api_keys SET revoked_at = UTC_TIMESTAMP() WHERE prefix = ? AND revoked_at IS NULL`
+
+	res, err := r.db.ExecContext(ctx, query, prefix)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		return 0, storage.ErrAPIKeyNotFound
+	}
+
+	return n, nil
+}
@@ -3,7 +3,11 @@ package redis
 import (
 	"context"
 	"fmt"
+	"main_service/internal/models"
+	"strconv"
+	"time"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -11,7 +15,100 @@ type RedisRepo struct {
 	client *redis.Client
 }
 
-const popKey = "popular_pastes"
+const (
+	popKey       = "popular_pastes"
+	rateLimitKey = "ratelimit:"
+	missKey      = "miss:"
+	enumKey      = "enum:"
+	contentKey   = "paste:"
+
+	// * канал, в который публикуются хэши удалённых из кэша текстов, чтобы реплики сбросили свой L1
+	invalidateChannel = "paste:invalidate"
+
+	contentDataField    = "data"
+	contentTypeField    = "type"
+	contentLangField    = "lang"
+	contentExpField     = "exp" // * время истечения текста, unix ms
+	contentDigestField  = "digest"
+	contentVisField     = "vis"
+	contentMutField     = "mut"     // * "1" — владелец может заменить содержимое
+	contentCreatedField = "created" // * время создания ревизии, unix ms
+	contentFields       = 8         // * сколько полей содержимого пишет SaveContent
+
+	// * maxViews — сколько представлений (страниц с подсветкой, сжатых копий) держать на один текст
+	maxViews = 16
+)
+
+// * recordMissScript считает промахи клиента в окне и при превышении порога выдаёт бан.
+// * Длительность бана удваивается с каждым следующим баном (strikes живут сутки) и ограничена maxBan.
+var recordMissScript = redis.NewScript(`
+local misses = redis.call('INCR', KEYS[1])
+if misses == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+
+if misses < tonumber(ARGV[2]) then
+	return 0
+end
+
+redis.call('DEL', KEYS[1])
+
+local strikes = redis.call('INCR', KEYS[2])
+redis.call('PEXPIRE', KEYS[2], 86400000)
+
+local ban = math.min(tonumber(ARGV[3]) * math.pow(2, strikes - 1), tonumber(ARGV[4]))
+redis.call('SET', KEYS[3], strikes, 'PX', ban)
+
+return ban
+`)
+
+// * saveViewScript добавляет поле в hash-ключ кэша, только если в нём уже лежит содержимое
+// * и полей меньше ARGV[4]. Уже сохранённое представление перезаписывается всегда.
+var saveViewScript = redis.NewScript(`
+if redis.call('HEXISTS', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+if redis.call('HEXISTS', KEYS[1], ARGV[2]) == 1 or redis.call('HLEN', KEYS[1]) < tonumber(ARGV[4]) then
+	redis.call('HSET', KEYS[1], ARGV[2], ARGV[3])
+end
+return 0
+`)
+
+// * tokenBucketScript атомарно пополняет bucket по времени redis и пытается взять один токен.
+// * Время берётся на стороне redis, чтобы реплики с расходящимися часами видели один и тот же bucket.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(data[1])
+local ts = tonumber(data[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) * 1000 / rate)
+end
+
+local reset = math.ceil((burst - tokens) * 1000 / rate)
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], reset + 1000)
+
+return {allowed, math.floor(tokens), retry, reset}
+`)
 
 func New(ctx context.Context, db int, addr string) (*RedisRepo, error) {
 	const op = "storage.redis.New"
@@ -21,6 +118,10 @@ func New(ctx context.Context, db int, addr string) (*RedisRepo, error) {
 		DB:   db,
 	})
 
+	if err := redisotel.InstrumentTracing(rdb); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	if err := rdb.Ping(ctx).Err(); err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -28,35 +129,181 @@ func New(ctx context.Context, db int, addr string) (*RedisRepo, error) {
 	return &RedisRepo{client: rdb}, nil
 }
 
-// * Text возвращает текст, если он есть в redis
-func (r *RedisRepo) Text(ctx context.Context, hash string) (string, error) {
-	key := hash
+// * Content возвращает закэшированное содержимое hash или nil, если его нет в redis.
+// * Содержимое хранится в hash-ключе вместе с MIME типом, значения в redis бинарно-безопасны.
+func (r *RedisRepo) Content(ctx context.Context, hash string) (*models.Content, error) {
+	key := contentKey + hash
 
-	res, err := r.client.Get(ctx, key).Result()
-	if err == redis.Nil {
-		return "", nil
+	res, err := r.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
 	}
 
-	return res, err
+	data, ok := res[contentDataField]
+	if !ok {
+		return nil, nil
+	}
+
+	// Без времени истечения нельзя проверить, что текст ещё жив, — считаем промахом
+	exp, err := strconv.ParseInt(res[contentExpField], 10, 64)
+	if err != nil {
+		return nil, nil
+	}
+
+	c := &models.Content{
+		Data:        data,
+		ContentType: res[contentTypeField],
+		Language:    res[contentLangField],
+		Digest:      res[contentDigestField],
+		Size:        int64(len(data)),
+		Visibility:  res[contentVisField],
+		Mutable:     res[contentMutField] == "1",
+		ExpiresAt:   time.UnixMilli(exp).UTC(),
+	}
+
+	if created, err := strconv.ParseInt(res[contentCreatedField], 10, 64); err == nil {
+		c.CreatedAt = time.UnixMilli(created).UTC()
+	}
+
+	return c, nil
+}
+
+// * SaveContent кладёт содержимое hash в кэш на ttl, заменяя значение любого типа под тем же ключом.
+// * Ключ всегда с TTL, поэтому его может вытеснить политика volatile-*.
+func (r *RedisRepo) SaveContent(ctx context.Context, hash string, c models.Content, ttl time.Duration) error {
+	key := contentKey + hash
+
+	mutable := ""
+	if c.Mutable {
+		mutable = "1"
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.HSet(ctx, key,
+		contentDataField, c.Data,
+		contentTypeField, c.ContentType,
+		contentLangField, c.Language,
+		contentExpField, c.ExpiresAt.UnixMilli(),
+		contentDigestField, c.Digest,
+		contentVisField, c.Visibility,
+		contentMutField, mutable,
+		contentCreatedField, c.CreatedAt.UnixMilli(),
+	)
+	pipe.PExpire(ctx, key, ttl)
+	_, err := pipe.Exec(ctx)
+
+	return err
 }
 
-func (r *RedisRepo) SaveText(ctx context.Context, hash, text string) error {
-	key := hash
+// * View возвращает закэшированное представление variant содержимого hash вместе со временем истечения текста
+// * или пустую строку, если представления нет
+func (r *RedisRepo) View(ctx context.Context, hash, variant string) (string, time.Time, error) {
+	key := contentKey + hash
 
-	return r.client.Set(ctx, key, text, 0).Err()
+	res, err := r.client.HMGet(ctx, key, variant, contentExpField).Result()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	view, _ := res[0].(string)
+	raw, _ := res[1].(string)
+
+	exp, err := strconv.ParseInt(raw, 10, 64)
+	if view == "" || err != nil {
+		return "", time.Time{}, nil
+	}
+
+	return view, time.UnixMilli(exp).UTC(), nil
 }
 
+// * SaveView кладёт представление variant рядом с содержимым hash.
+// * Если самого содержимого в кэше нет (текст не популярен) или у него уже maxViews представлений, ничего не делает.
+// * HSET не трогает TTL ключа, поэтому представление истекает вместе с содержимым.
+func (r *RedisRepo) SaveView(ctx context.Context, hash, variant, view string) error {
+	key := contentKey + hash
+
+	return saveViewScript.Run(ctx, r.client, []string{key}, contentDataField, variant, view, contentFields+maxViews).Err()
+}
+
+// * DeleteText удаляет hash из кэша и оповещает об этом все реплики через invalidateChannel
 func (r *RedisRepo) DeleteText(ctx context.Context, hash string) error {
-	key := hash
+	key := contentKey + hash
+
+	pipe := r.client.Pipeline()
+	pipe.Del(ctx, key)
+	pipe.Publish(ctx, invalidateChannel, hash)
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+// * Invalidations возвращает хэши, удалённые из кэша на любой реплике, пока не отменён ctx.
+// * Сообщения, пришедшие во время переподключения к redis, теряются.
+func (r *RedisRepo) Invalidations(ctx context.Context) <-chan string {
+	sub := r.client.Subscribe(ctx, invalidateChannel)
+	out := make(chan string)
+
+	go func() {
+		defer close(out)
+		defer sub.Close()
 
-	return r.client.Del(ctx, key).Err()
+		msgs := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				select {
+				case out <- msg.Payload:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out
 }
 
-// * IncPopularity увеличивает популярность конкретного hash
+// * IncPopularity увеличивает популярность конкретного hash и учитывает просмотр в трендах.
+// * Возвращает число просмотров за всё время.
 func (r *RedisRepo) IncPopularity(ctx context.Context, hash string) (int64, error) {
-	res, err := r.client.ZIncrBy(ctx, popKey, 1, hash).Result()
+	pipe := r.client.Pipeline()
+	total := pipe.ZIncrBy(ctx, popKey, 1, hash)
+	recordView(ctx, pipe, hash, 1, time.Now())
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	return int64(total.Val()), nil
+}
+
+// * AddViews одним pipeline добавляет накопленные просмотры в популярность и тренды
+func (r *RedisRepo) AddViews(ctx context.Context, views map[string]int64) error {
+	const op = "storage.redis.AddViews"
+
+	if len(views) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+
+	pipe := r.client.Pipeline()
+	for hash, n := range views {
+		pipe.ZIncrBy(ctx, popKey, float64(n), hash)
+		recordView(ctx, pipe, hash, n, now)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
 
-	return int64(res), err
+	return nil
 }
 
 // * Top возвращает топ популярности
@@ -64,9 +311,113 @@ func (r *RedisRepo) Top(ctx context.Context, limit int) ([]redis.Z, error) {
 	return r.client.ZRevRangeWithScores(ctx, popKey, 0, int64(limit)-1).Result()
 }
 
-// * Delete удаляет hash
+// * Delete удаляет hash из рейтинга популярности и из ещё живых бакетов трендов
 func (r *RedisRepo) Delete(ctx context.Context, hash string) error {
-	return r.client.ZRem(ctx, popKey, hash).Err()
+	const op = "storage.redis.Delete"
+
+	pipe := r.client.Pipeline()
+	pipe.ZRem(ctx, popKey, hash)
+	for _, key := range liveTrendingBuckets(time.Now()) {
+		pipe.ZRem(ctx, key, hash)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// * DecayPopularity умножает все счётчики популярности на factor и убирает тексты, у которых остаётся
+// * меньше одного просмотра. Так рейтинг отражает недавние просмотры, а не накопленные за всё время.
+func (r *RedisRepo) DecayPopularity(ctx context.Context, factor float64) error {
+	const op = "storage.redis.DecayPopularity"
+
+	pipe := r.client.TxPipeline()
+	pipe.ZUnionStore(ctx, popKey, &redis.ZStore{Keys: []string{popKey}, Weights: []float64{factor}})
+	pipe.ZRemRangeByScore(ctx, popKey, "-inf", "(1")
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// * TakeToken пытается взять токен из bucket key.
+// * rate — скорость пополнения в токенах в секунду, burst — ёмкость bucket.
+func (r *RedisRepo) TakeToken(ctx context.Context, key string, rate float64, burst int) (models.RateLimitResult, error) {
+	const op = "storage.redis.TakeToken"
+
+	res, err := tokenBucketScript.Run(ctx, r.client,
+		[]string{rateLimitKey + key},
+		strconv.FormatFloat(rate, 'f', -1, 64), burst,
+	).Int64Slice()
+	if err != nil {
+		return models.RateLimitResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return models.RateLimitResult{
+		Allowed:    res[0] == 1,
+		Remaining:  int(res[1]),
+		RetryAfter: time.Duration(res[2]) * time.Millisecond,
+		Reset:      time.Duration(res[3]) * time.Millisecond,
+	}, nil
+}
+
+// * Miss возвращает причину недавнего промаха по hash или пустую строку
+func (r *RedisRepo) Miss(ctx context.Context, hash string) (string, error) {
+	res, err := r.client.Get(ctx, missKey+hash).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+
+	return res, err
+}
+
+// * SaveMiss запоминает промах по hash на ttl, чтобы повторные запросы не ходили в MySQL
+func (r *RedisRepo) SaveMiss(ctx context.Context, hash, reason string, ttl time.Duration) error {
+	return r.client.Set(ctx, missKey+hash, reason, ttl).Err()
+}
+
+// * DeleteMiss забывает промах по hash, например когда под ним только что сохранили текст
+func (r *RedisRepo) DeleteMiss(ctx context.Context, hash string) error {
+	return r.client.Del(ctx, missKey+hash).Err()
+}
+
+// * BanTTL возвращает, сколько ещё длится бан клиента за перебор хэшей (0 — бана нет)
+func (r *RedisRepo) BanTTL(ctx context.Context, client string) (time.Duration, error) {
+	const op = "storage.redis.BanTTL"
+
+	ttl, err := r.client.PTTL(ctx, enumKey+"ban:"+client).Result()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// -2: ключа нет, -1: ключ без TTL (не должен встречаться)
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+// * RecordMiss учитывает промах клиента и возвращает длительность бана, если порог превышен
+func (r *RedisRepo) RecordMiss(ctx context.Context, client string, window time.Duration, threshold int, baseBan, maxBan time.Duration) (time.Duration, error) {
+	const op = "storage.redis.RecordMiss"
+
+	ban, err := recordMissScript.Run(ctx, r.client,
+		[]string{enumKey + "misses:" + client, enumKey + "strikes:" + client, enumKey + "ban:" + client},
+		window.Milliseconds(), threshold, baseBan.Milliseconds(), maxBan.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return time.Duration(ban) * time.Millisecond, nil
+}
+
+// * Ping проверяет доступность redis
+func (r *RedisRepo) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
 }
 
 func (r *RedisRepo) Close() {
@@ -0,0 +1,128 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"main_service/internal/models"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	trendingHourKey  = "trending:h:"     // * + 2026101914, просмотры за час
+	trendingDayKey   = "trending:d:"     // * + 20261019, просмотры за сутки
+	trendingUnionKey = "trending:union:" // * + окно, готовое объединение бакетов
+
+	// * бакеты живут чуть дольше самого длинного окна, в которое попадают
+	trendingHourTTL = 26 * time.Hour
+	trendingDayTTL  = 8 * 24 * time.Hour
+
+	// * как долго отдаётся одно и то же объединение бакетов, чтобы не считать его на каждый запрос
+	trendingUnionTTL = time.Minute
+)
+
+// * trendingBuckets возвращает ключи бакетов окна window на момент now, от нового к старому.
+// * hour — текущий и предыдущий час, day — последние 24 часа, week — последние 7 суток.
+func trendingBuckets(window string, now time.Time) ([]string, error) {
+	now = now.UTC()
+
+	var (
+		n    int
+		step time.Duration
+		key  func(t time.Time) string
+	)
+
+	hourKey := func(t time.Time) string { return trendingHourKey + t.Format("2006010215") }
+	dayKey := func(t time.Time) string { return trendingDayKey + t.Format("20060102") }
+
+	switch window {
+	case models.TrendingHour:
+		n, step, key = 2, time.Hour, hourKey
+	case models.TrendingDay:
+		n, step, key = 24, time.Hour, hourKey
+	case models.TrendingWeek:
+		n, step, key = 7, 24*time.Hour, dayKey
+	default:
+		return nil, fmt.Errorf("unknown trending window %q", window)
+	}
+
+	keys := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		keys = append(keys, key(now.Add(-time.Duration(i)*step)))
+	}
+
+	return keys, nil
+}
+
+// * liveTrendingBuckets возвращает ключи всех бакетов, которые на момент now ещё могут не истечь,
+// * вместе с готовыми объединениями окон. TTL бакета продлевается до конца его часа или суток.
+func liveTrendingBuckets(now time.Time) []string {
+	now = now.UTC()
+
+	var keys []string
+	for t := now; now.Sub(t) < trendingHourTTL+time.Hour; t = t.Add(-time.Hour) {
+		keys = append(keys, trendingHourKey+t.Format("2006010215"))
+	}
+	for t := now; now.Sub(t) < trendingDayTTL+24*time.Hour; t = t.Add(-24 * time.Hour) {
+		keys = append(keys, trendingDayKey+t.Format("20060102"))
+	}
+	for _, window := range []string{models.TrendingHour, models.TrendingDay, models.TrendingWeek} {
+		keys = append(keys, trendingUnionKey+window)
+	}
+
+	return keys
+}
+
+// * recordView добавляет n просмотров hash в часовой и суточный бакеты трендов
+func recordView(ctx context.Context, pipe redis.Pipeliner, hash string, n int64, now time.Time) {
+	now = now.UTC()
+
+	hourKey := trendingHourKey + now.Format("2006010215")
+	dayKey := trendingDayKey + now.Format("20060102")
+
+	pipe.ZIncrBy(ctx, hourKey, float64(n), hash)
+	pipe.Expire(ctx, hourKey, trendingHourTTL)
+	pipe.ZIncrBy(ctx, dayKey, float64(n), hash)
+	pipe.Expire(ctx, dayKey, trendingDayTTL)
+}
+
+// * Trending возвращает limit самых просматриваемых за окно window текстов.
+// * Объединение бакетов кэшируется на trendingUnionTTL.
+func (r *RedisRepo) Trending(ctx context.Context, window string, limit int) ([]models.TrendingPaste, error) {
+	const op = "storage.redis.Trending"
+
+	keys, err := trendingBuckets(window, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	union := trendingUnionKey + window
+
+	n, err := r.client.Exists(ctx, union).Result()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n == 0 {
+		pipe := r.client.TxPipeline()
+		pipe.ZUnionStore(ctx, union, &redis.ZStore{Keys: keys})
+		pipe.Expire(ctx, union, trendingUnionTTL)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	res, err := r.client.ZRevRangeWithScores(ctx, union, 0, int64(limit)-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	pastes := make([]models.TrendingPaste, 0, len(res))
+	for _, z := range res {
+		member, _ := z.Member.(string)
+		pastes = append(pastes, models.TrendingPaste{Hash: member, Views: int64(z.Score)})
+	}
+
+	return pastes, nil
+}
@@ -0,0 +1,48 @@
+package redis
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"main_service/internal/models"
+)
+
+func TestLiveTrendingBucketsCoverEveryWindow(t *testing.T) {
+	now := time.Date(2026, 10, 19, 14, 30, 0, 0, time.UTC)
+	live := liveTrendingBuckets(now)
+
+	// Всё, что читает Trending, должно чиститься при удалении текста
+	for _, window := range []string{models.TrendingHour, models.TrendingDay, models.TrendingWeek} {
+		keys, err := trendingBuckets(window, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, key := range append(keys, trendingUnionKey+window) {
+			if !slices.Contains(live, key) {
+				t.Errorf("%s bucket %s is not cleaned", window, key)
+			}
+		}
+	}
+
+	for _, key := range []string{
+		trendingHourKey + "2026101914",
+		trendingHourKey + "2026101813", // * 25 часов назад, ещё жив
+		trendingDayKey + "20261012",    // * 7 суток назад, ещё жив
+		trendingDayKey + "20261011",    // * писался до конца суток, живёт до 19.10 23:59
+	} {
+		if !slices.Contains(live, key) {
+			t.Errorf("live bucket %s is not cleaned", key)
+		}
+	}
+
+	for _, key := range []string{
+		trendingHourKey + "2026101811", // * последняя запись в 11:59 18.10, истёк в 13:59 19.10
+		trendingDayKey + "20261010",
+	} {
+		if slices.Contains(live, key) {
+			t.Errorf("expired bucket %s is listed", key)
+		}
+	}
+}
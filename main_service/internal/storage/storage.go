@@ -1,8 +1,46 @@
 package storage
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
-	ErrTTLIsExpired = errors.New("ttl is expired")
-	ErrTextNotFound = errors.New("text is not found")
+	ErrTTLIsExpired   = errors.New("ttl is expired")
+	ErrTextNotFound   = errors.New("text is not found")
+	ErrUserNotFound   = errors.New("user is not found")
+	ErrUserExists     = errors.New("user already exists")
+	ErrAPIKeyNotFound = errors.New("api key is not found")
+	ErrNotOwner       = errors.New("text belongs to another user")
+	ErrRevNotFound    = errors.New("revision is not found")
+	ErrFileNotFound   = errors.New("file is not found")
+	ErrBinaryContent  = errors.New("content is not text")
+	ErrTakenDown      = errors.New("text is taken down")
+	ErrHasFiles       = errors.New("text has multiple files")
 )
+
+// * TakenDownError — текст скрыт или удалён модератором. errors.Is(err, ErrTakenDown) для него истинно.
+type TakenDownError struct {
+	Reason string
+}
+
+func (e *TakenDownError) Error() string {
+	return ErrTakenDown.Error() + ": " + e.Reason
+}
+
+func (e *TakenDownError) Is(target error) bool {
+	return target == ErrTakenDown
+}
+
+// * ExpiredError — срок жизни текста истёк в ExpiresAt. errors.Is(err, ErrTTLIsExpired) для него истинно.
+type ExpiredError struct {
+	ExpiresAt time.Time
+}
+
+func (e *ExpiredError) Error() string {
+	return ErrTTLIsExpired.Error() + " at " + e.ExpiresAt.UTC().Format(time.RFC3339)
+}
+
+func (e *ExpiredError) Is(target error) bool {
+	return target == ErrTTLIsExpired
+}
@@ -0,0 +1,31 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS users (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  name VARCHAR(255) NOT NULL,
+  is_admin BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_users_name ON users(name);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  user_id BIGINT UNSIGNED NOT NULL,
+  key_hash CHAR(64) NOT NULL,
+  prefix VARCHAR(16) NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  revoked_at TIMESTAMP NULL,
+  CONSTRAINT fk_api_keys_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_api_keys_key_hash ON api_keys(key_hash);
+CREATE INDEX idx_api_keys_prefix ON api_keys(prefix);
+
+ALTER TABLE pastes ADD COLUMN owner_id BIGINT UNSIGNED NULL;
+CREATE INDEX idx_pastes_owner_created ON pastes(owner_id, created_at);
+
+-- +goose Down
+DROP INDEX idx_pastes_owner_created ON pastes;
+ALTER TABLE pastes DROP COLUMN owner_id;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE pastes ADD COLUMN visibility VARCHAR(16) NOT NULL DEFAULT 'public';
+
+-- +goose Down
+ALTER TABLE pastes DROP COLUMN visibility;
@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE pastes
+  ADD COLUMN current_rev INT UNSIGNED NOT NULL DEFAULT 1,
+  ADD COLUMN last_rev INT UNSIGNED NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS paste_revisions (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  paste_hash VARCHAR(64) NOT NULL,
+  rev INT UNSIGNED NOT NULL,
+  object_key VARCHAR(255) NOT NULL,
+  size BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  author_id BIGINT UNSIGNED NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT fk_paste_revisions_paste FOREIGN KEY (paste_hash) REFERENCES pastes(hash) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_paste_revisions_hash_rev ON paste_revisions(paste_hash, rev);
+
+-- * Тексты, сохранённые до ревизий, лежат в MinIO как <hash>.txt
+INSERT INTO paste_revisions (paste_hash, rev, object_key, size, author_id, created_at)
+SELECT hash, 1, CONCAT(hash, '.txt'), 0, owner_id, created_at FROM pastes;
+
+-- +goose Down
+DROP TABLE IF EXISTS paste_revisions;
+ALTER TABLE pastes DROP COLUMN last_rev, DROP COLUMN current_rev;
@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE pastes ADD COLUMN parent_hash VARCHAR(64) NULL;
+CREATE INDEX idx_pastes_parent_hash ON pastes(parent_hash);
+ALTER TABLE pastes
+  ADD CONSTRAINT fk_pastes_parent FOREIGN KEY (parent_hash) REFERENCES pastes(hash) ON DELETE SET NULL;
+
+-- * Форк без изменений ссылается на объект исходного текста, перед удалением объекта считаем ссылки
+CREATE INDEX idx_paste_revisions_object_key ON paste_revisions(object_key);
+
+-- +goose Down
+DROP INDEX idx_paste_revisions_object_key ON paste_revisions;
+ALTER TABLE pastes DROP FOREIGN KEY fk_pastes_parent;
+DROP INDEX idx_pastes_parent_hash ON pastes;
+ALTER TABLE pastes DROP COLUMN parent_hash;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS paste_files (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  paste_hash VARCHAR(64) NOT NULL,
+  position INT UNSIGNED NOT NULL,
+  filename VARCHAR(255) NOT NULL,
+  language VARCHAR(32) NOT NULL DEFAULT '',
+  object_key VARCHAR(255) NOT NULL,
+  size BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  CONSTRAINT fk_paste_files_paste FOREIGN KEY (paste_hash) REFERENCES pastes(hash) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_paste_files_hash_filename ON paste_files(paste_hash, filename);
+CREATE INDEX idx_paste_files_object_key ON paste_files(object_key);
+
+-- +goose Down
+DROP TABLE IF EXISTS paste_files;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE paste_revisions ADD COLUMN content_type VARCHAR(127) NOT NULL DEFAULT 'text/plain';
+
+-- +goose Down
+ALTER TABLE paste_revisions DROP COLUMN content_type;
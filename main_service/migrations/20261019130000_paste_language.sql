@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE pastes
+  ADD COLUMN language VARCHAR(32) NOT NULL DEFAULT '',
+  ADD COLUMN language_confidence FLOAT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE pastes DROP COLUMN language_confidence, DROP COLUMN language;
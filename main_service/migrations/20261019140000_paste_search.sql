@@ -0,0 +1,12 @@
+-- +goose Up
+-- * Полнотекстовый индекс по содержимому текстов. Документ удаляется каскадно вместе с текстом.
+-- * Заполнить индекс для уже сохранённых текстов: ./reindex
+CREATE TABLE IF NOT EXISTS paste_search (
+  paste_hash VARCHAR(64) NOT NULL PRIMARY KEY,
+  body MEDIUMTEXT NOT NULL,
+  CONSTRAINT fk_paste_search_paste FOREIGN KEY (paste_hash) REFERENCES pastes(hash) ON DELETE CASCADE,
+  FULLTEXT INDEX ft_paste_search_body (body)
+) ENGINE=InnoDB;
+
+-- +goose Down
+DROP TABLE IF EXISTS paste_search;
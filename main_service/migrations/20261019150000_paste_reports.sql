@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE pastes
+  ADD COLUMN moderation VARCHAR(16) NOT NULL DEFAULT '',
+  ADD COLUMN moderation_reason VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS paste_reports (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  paste_hash VARCHAR(64) NOT NULL,
+  reporter_key CHAR(64) NOT NULL,
+  reporter_id BIGINT UNSIGNED NULL,
+  reason VARCHAR(32) NOT NULL,
+  comment VARCHAR(1000) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL,
+  CONSTRAINT fk_paste_reports_paste FOREIGN KEY (paste_hash) REFERENCES pastes(hash) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_paste_reports_hash_reporter ON paste_reports(paste_hash, reporter_key);
+
+-- +goose Down
+DROP TABLE IF EXISTS paste_reports;
+ALTER TABLE pastes DROP COLUMN moderation_reason, DROP COLUMN moderation;
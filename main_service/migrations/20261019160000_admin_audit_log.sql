@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  admin_id BIGINT UNSIGNED NOT NULL,
+  action VARCHAR(128) NOT NULL,
+  paste_hash VARCHAR(64) NULL,
+  params TEXT NOT NULL,
+  status SMALLINT UNSIGNED NOT NULL,
+  request_id VARCHAR(64) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL
+);
+
+-- * Без внешнего ключа на pastes: запись об удалении текста должна пережить сам текст
+CREATE INDEX idx_admin_audit_log_created_at ON admin_audit_log(created_at);
+CREATE INDEX idx_admin_audit_log_paste_hash ON admin_audit_log(paste_hash);
+
+-- +goose Down
+DROP TABLE IF EXISTS admin_audit_log;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- sha256 содержимого ревизии в hex: по нему отвечают на условные GET, не читая объект из MinIO.
+-- У ревизий, сохранённых раньше, поле пустое.
+ALTER TABLE paste_revisions ADD COLUMN digest CHAR(64) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE paste_revisions DROP COLUMN digest;
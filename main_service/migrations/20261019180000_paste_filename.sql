@@ -0,0 +1,6 @@
+-- +goose Up
+-- Имя файла, которое автор указал при сохранении: по нему язык определяется заново после правки.
+ALTER TABLE pastes ADD COLUMN filename VARCHAR(255) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE pastes DROP COLUMN filename;